@@ -2,72 +2,111 @@
 package api
 
 import (
+	"errors"
+	"log"
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
 
+	"github.com/JkD004/playarena-backend/jwtkeys"
+	"github.com/JkD004/playarena-backend/policy"
 	"github.com/JkD004/playarena-backend/user"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-// AuthMiddleware creates a "guard" for your routes
-func AuthMiddleware(allowedRoles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 1. Get the "Authorization" header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			return
-		}
-
-		// 2. Extract the Bearer token
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Bearer token required"})
-			return
-		}
+// resolveAuth looks up the live account state behind a token (replaced in tests)
+var resolveAuth = user.ResolveAuth
 
-		// 3. Prepare claims
-		claims := &user.Claims{}
+// Authorize creates a "guard" for a route. It authenticates the bearer
+// token, then asks the policy whether the user may perform the permission.
+// For permissions tied to a resource, the route's :id is the resource.
+func Authorize(permission policy.Permission) gin.HandlerFunc {
+	rule := policy.RuleFor(permission) // unknown permissions fail at startup
 
-		// --- SECURITY FIX: Read JWT Secret from ENV ---
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Server config error"})
-			return
-		}
-		// ------------------------------------------------
-
-		// 4. Parse the token
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+	return func(c *gin.Context) {
+		account, ok := authenticate(c)
+		if !ok {
 			return
 		}
+		subject := policy.Subject{UserID: account.UserID, Role: account.Role, EmailVerified: account.EmailVerified}
 
-		// 5. Check role permissions
-		isAllowed := false
-		for _, role := range allowedRoles {
-			if claims.Role == role {
-				isAllowed = true
-				break
+		var resourceID int64
+		if rule.Resource != "" {
+			id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+				return
 			}
+			resourceID = id
 		}
 
-		if !isAllowed {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You do not have permission"})
+		if err := policy.Can(subject, permission, resourceID); err != nil {
+			switch {
+			case errors.Is(err, policy.ErrForbidden):
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You do not have permission"})
+			case errors.Is(err, policy.ErrEmailNotVerified):
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Please verify your email address first"})
+			case errors.Is(err, policy.ErrNotFound):
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			default:
+				log.Println("Error checking permission", permission, ":", err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+			}
 			return
 		}
 
-		// 6. Success — attach user data to context
-		c.Set("userID", claims.UserID)
-		c.Set("userRole", claims.Role)
+		// Success — attach user data to context
+		c.Set("userID", account.UserID)
+		c.Set("userRole", account.Role)
 
 		// Continue to next handler
 		c.Next()
 	}
 }
+
+// authenticate validates the bearer token and returns the live state of its
+// account; on failure it aborts the request
+func authenticate(c *gin.Context) (*user.AuthState, bool) {
+	// 1. Get the "Authorization" header
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		return nil, false
+	}
+
+	// 2. Extract the Bearer token
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Bearer token required"})
+		return nil, false
+	}
+
+	// 3. Verify it against the signing keys (kid and alg must match a key)
+	claims := &user.Claims{}
+	token, err := jwtkeys.Parse(tokenString, claims)
+	if errors.Is(err, jwtkeys.ErrNotConfigured) {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Server config error"})
+		return nil, false
+	}
+	if err != nil || !token.Valid {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return nil, false
+	}
+
+	// 4. Check the account is still in good standing and use its current
+	// role; the one in the token may be out of date
+	state, err := resolveAuth(claims)
+	switch {
+	case errors.Is(err, user.ErrAccountSuspended):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Account suspended"})
+		return nil, false
+	case errors.Is(err, user.ErrTokenRevoked), errors.Is(err, user.ErrUserNotFound):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expired, please log in again"})
+		return nil, false
+	case err != nil:
+		log.Println("Error resolving user", claims.UserID, ":", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not verify account"})
+		return nil, false
+	}
+	return state, true
+}
@@ -0,0 +1,80 @@
+// api/policy.go
+package api
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/JkD004/playarena-backend/booking"
+	"github.com/JkD004/playarena-backend/notification"
+	"github.com/JkD004/playarena-backend/policy"
+	"github.com/JkD004/playarena-backend/team"
+	"github.com/JkD004/playarena-backend/venue"
+)
+
+// registerPolicy wires the policy package to the repositories it needs to
+// resolve resources and relationships
+func registerPolicy() {
+	policy.RegisterLoader(policy.ResourceVenue, func(id int64) (*policy.Resource, error) {
+		v, err := venue.FindVenueByID(id)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		return &policy.Resource{OwnerID: v.OwnerID, VenueID: v.ID}, nil
+	})
+	policy.RegisterLoader(policy.ResourcePhoto, func(id int64) (*policy.Resource, error) {
+		venueID, err := venue.GetVenueIdFromPhoto(id)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		return &policy.Resource{VenueID: venueID}, nil
+	})
+	policy.RegisterLoader(policy.ResourceBooking, func(id int64) (*policy.Resource, error) {
+		b, err := booking.FindBookingByID(id)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		return &policy.Resource{OwnerID: b.UserID, VenueID: b.VenueID}, nil
+	})
+	policy.RegisterLoader(policy.ResourceReview, func(id int64) (*policy.Resource, error) {
+		r, err := venue.FindReviewByID(id)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		return &policy.Resource{OwnerID: r.UserID, VenueID: r.VenueID}, nil
+	})
+	policy.RegisterLoader(policy.ResourceNotification, func(id int64) (*policy.Resource, error) {
+		n, err := notification.FindNotificationByID(id)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		return &policy.Resource{OwnerID: n.UserID}, nil
+	})
+	policy.RegisterLoader(policy.ResourceTeam, func(id int64) (*policy.Resource, error) {
+		t, err := team.GetTeamByID(id)
+		if err != nil {
+			return nil, notFound(err)
+		}
+		return &policy.Resource{OwnerID: t.OwnerID, TeamID: t.ID}, nil
+	})
+
+	policy.SetVenueAccess(func(venueID, userID int64, staffPermission string) (bool, error) {
+		if staffPermission == "" {
+			return venue.IsVenueOwner(venueID, userID)
+		}
+		err := venue.VerifyVenuePermission(venueID, userID, "", staffPermission)
+		if errors.Is(err, venue.ErrNoVenuePermission) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	policy.SetTeamMembership(team.IsUserMember)
+}
+
+// notFound maps a missing row to policy.ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return policy.ErrNotFound
+	}
+	return err
+}
@@ -3,76 +3,157 @@ package api
 
 import (
 	"github.com/JkD004/playarena-backend/booking"
+	"github.com/JkD004/playarena-backend/jwtkeys"
 	"github.com/JkD004/playarena-backend/team"
 	"github.com/JkD004/playarena-backend/user"
 	"github.com/JkD004/playarena-backend/venue"
 	"github.com/gin-gonic/gin"
 	"github.com/JkD004/playarena-backend/notification"
+	"github.com/JkD004/playarena-backend/league"
+	"github.com/JkD004/playarena-backend/policy"
+	"github.com/JkD004/playarena-backend/rating"
+	"github.com/JkD004/playarena-backend/taxonomy"
 )
 
 func SetupRoutes(router *gin.Engine) {
+	registerPolicy()
+
+	// Public keys for services that verify our tokens (RS256/EdDSA only)
+	router.GET("/.well-known/jwks.json", jwtkeys.JWKSHandler)
 
 	v1 := router.Group("/api/v1")
 	{
 		// === Public Routes (No Auth Needed) ===
 		v1.POST("/register", user.RegisterUserHandler)
 		v1.POST("/login", user.LoginUserHandler)
+		v1.POST("/token/refresh", user.RefreshTokenHandler) // Rotates the refresh token
+		v1.POST("/logout", user.LogoutHandler)              // Ends the session of the refresh token in the body
+		v1.GET("/verify-email", user.VerifyEmailHandler)    // Link from the verification email
+		v1.POST("/password/forgot", user.ForgotPasswordHandler)
+		v1.POST("/password/reset", user.ResetPasswordHandler)
+		v1.POST("/login/otp/send", user.SendLoginOTPHandler) // Texts a one-time code
+		v1.POST("/login/otp", user.OTPLoginHandler)
 		v1.GET("/venues", venue.GetVenuesHandler)
+		v1.GET("/venues/available", booking.SearchAvailableVenuesHandler) // Venues free for a whole time window
 		v1.GET("/venues/:id", venue.GetVenueByIDHandler)
 		v1.GET("/venues/:id/photos", venue.GetVenuePhotosHandler) // Public can see photos
+		v1.GET("/sports", taxonomy.GetSportsHandler)
+		v1.GET("/amenities", taxonomy.GetAmenitiesHandler)
 
 		// === Admin-Only Routes ===
-		v1.GET("/admin/venues", AuthMiddleware("admin"), venue.GetVenuesByStatusHandler)
-		v1.PATCH("/admin/venues/:id/status", AuthMiddleware("admin"), venue.UpdateVenueStatusHandler)
-		v1.GET("/admin/bookings", AuthMiddleware("admin"), booking.GetAllBookingsHandler)
-
-		// === Owner & Admin Routes ===
-		v1.POST("/venues", AuthMiddleware("player", "owner", "admin"), venue.CreateVenueHandler)
-		v1.POST("/venues/:id/photos", AuthMiddleware("owner", "admin"), venue.UploadVenuePhotoHandler)
-		v1.DELETE("/photos/:id", AuthMiddleware("owner", "admin"), venue.DeleteVenuePhotoHandler)
+		v1.GET("/admin/venues", Authorize(policy.VenueListAll), venue.GetVenuesByStatusHandler)
+		v1.PATCH("/admin/venues/:id/status", Authorize(policy.VenueDecide), venue.UpdateVenueStatusHandler)
+		v1.GET("/admin/bookings", Authorize(policy.BookingListAll), booking.GetAllBookingsHandler)
+		v1.GET("/admin/reviews/reports", Authorize(policy.ReviewModerate), venue.GetModerationQueueHandler)
+		v1.PATCH("/admin/reviews/:id", Authorize(policy.ReviewModerate), venue.ModerateReviewHandler)
+		v1.DELETE("/admin/reviews/:id", Authorize(policy.ReviewModerate), venue.AdminDeleteReviewHandler)
+		v1.GET("/admin/venue-revisions", Authorize(policy.RevisionDecide), venue.GetPendingRevisionsHandler)
+		v1.PATCH("/admin/venue-revisions/:id", Authorize(policy.RevisionDecide), venue.DecideRevisionHandler)
+		v1.GET("/admin/sports", Authorize(policy.TaxonomyManage), taxonomy.AdminGetSportsHandler)
+		v1.POST("/admin/sports", Authorize(policy.TaxonomyManage), taxonomy.CreateSportHandler)
+		v1.PATCH("/admin/sports/:id", Authorize(policy.TaxonomyManage), taxonomy.UpdateSportHandler)
+		v1.GET("/admin/amenities", Authorize(policy.TaxonomyManage), taxonomy.AdminGetAmenitiesHandler)
+		v1.POST("/admin/amenities", Authorize(policy.TaxonomyManage), taxonomy.CreateAmenityHandler)
+		v1.PATCH("/admin/amenities/:id", Authorize(policy.TaxonomyManage), taxonomy.UpdateAmenityHandler)
+		v1.PATCH("/admin/users/:id/status", Authorize(policy.UserManage), user.UpdateUserStatusHandler)
+		v1.PATCH("/admin/users/:id/role", Authorize(policy.UserManage), user.UpdateUserRoleHandler)
+
+		// === Venue Management (owner, admin and permitted staff) ===
+		v1.POST("/venues", Authorize(policy.VenueCreate), venue.CreateVenueHandler)
+		// Until approval the submitter is still a player; the policy checks ownership
+		v1.POST("/venues/:id/resubmit", Authorize(policy.VenueResubmit), venue.ResubmitVenueHandler)
+		v1.GET("/venues/:id/history", Authorize(policy.VenueEdit), venue.GetVenueStatusHistoryHandler)
+		v1.POST("/venues/:id/photos", Authorize(policy.VenueEdit), venue.UploadVenuePhotoHandler)
+		v1.DELETE("/photos/:id", Authorize(policy.PhotoEdit), venue.DeleteVenuePhotoHandler)
+		v1.PATCH("/photos/:id", Authorize(policy.PhotoEdit), venue.UpdateVenuePhotoHandler)
+		v1.PUT("/venues/:id/photos/order", Authorize(policy.VenueEdit), venue.ReorderVenuePhotosHandler)
+		v1.PUT("/venues/:id/cover", Authorize(policy.VenueEdit), venue.SetCoverPhotoHandler)
+
+		// === Venue Staff ===
+		// Staff are ordinary accounts; see the venue rules in policy
+		v1.GET("/venues/:id/staff", Authorize(policy.VenueManage), venue.GetVenueStaffHandler)
+		v1.POST("/venues/:id/staff", Authorize(policy.VenueManage), venue.InviteStaffHandler)
+		v1.GET("/venues/:id/staff/actions", Authorize(policy.VenueManage), venue.GetStaffActionsHandler)
+		v1.PATCH("/venues/:id/staff/:user_id", Authorize(policy.VenueManage), venue.UpdateStaffHandler)
+		v1.DELETE("/venues/:id/staff/:user_id", Authorize(policy.VenueManage), venue.RemoveStaffHandler)
+		v1.GET("/staff/venues", Authorize(policy.StaffListOwn), venue.GetMyStaffVenuesHandler)
+		v1.PATCH("/staff/venues/:id", Authorize(policy.StaffRespond), venue.RespondToStaffInviteHandler)
+		v1.POST("/bookings/:id/check-in", Authorize(policy.BookingCheckIn), booking.CheckInBookingHandler)
 
 		// === All Logged-in Users (Player, Owner, Admin) ===
-		v1.POST("/bookings", AuthMiddleware("player", "owner", "admin"), booking.CreateBookingHandler)
-		v1.POST("/bookings/:id/pay", AuthMiddleware("player", "owner", "admin"), booking.ProcessPaymentHandler)
-		v1.GET("/bookings/mine", AuthMiddleware("player", "owner", "admin"), booking.GetUserBookingsHandler)
-		v1.PATCH("/bookings/:id/cancel", AuthMiddleware("player", "owner", "admin"), booking.CancelBookingHandler)
+		v1.POST("/bookings", Authorize(policy.BookingCreate), booking.CreateBookingHandler)
+		v1.POST("/bookings/:id/pay", Authorize(policy.BookingPay), booking.ProcessPaymentHandler)
+		v1.GET("/bookings/mine", Authorize(policy.BookingListOwn), booking.GetUserBookingsHandler)
+		v1.PATCH("/bookings/:id/cancel", Authorize(policy.BookingCancel), booking.CancelBookingHandler)
 
 		// === Team Routes ===
-		// (We'll use "player", "owner", "admin" for now on team routes for simplicity)
-		v1.POST("/teams", AuthMiddleware("player", "owner", "admin"), team.CreateTeamHandler)
-		v1.GET("/teams/mine", AuthMiddleware("player", "owner", "admin"), team.GetMyTeamsHandler)
-		v1.PATCH("/teams/:id/status", AuthMiddleware("player", "owner", "admin"), team.UpdateMemberStatusHandler)
-		v1.POST("/teams/:id/invite", AuthMiddleware("player", "owner", "admin"), team.InviteMemberHandler)
-		v1.GET("/teams/:id/members", AuthMiddleware("player", "owner", "admin"), team.GetTeamMembersHandler)
+		// (Membership is checked by the policy for team-scoped routes)
+		v1.POST("/teams", Authorize(policy.TeamCreate), team.CreateTeamHandler)
+		v1.GET("/teams/mine", Authorize(policy.TeamListOwn), team.GetMyTeamsHandler)
+		v1.PATCH("/teams/:id/status", Authorize(policy.TeamRespond), team.UpdateMemberStatusHandler)
+		v1.POST("/teams/:id/invite", Authorize(policy.TeamInvite), team.InviteMemberHandler)
+		v1.GET("/teams/:id/members", Authorize(policy.TeamViewMembers), team.GetTeamMembersHandler)
 
-		v1.GET("/venues/mine", AuthMiddleware("owner", "admin"), venue.GetOwnerVenuesHandler)
+		v1.GET("/venues/mine", Authorize(policy.VenueListOwn), venue.GetOwnerVenuesHandler)
 
-		v1.GET("/venues/:id/bookings", AuthMiddleware("owner", "admin"), booking.GetVenueBookingsHandler)
+		v1.GET("/venues/:id/bookings", Authorize(policy.VenueViewBookings), booking.GetVenueBookingsHandler)
 
-		v1.GET("/owner/venues/:id/stats", AuthMiddleware("owner", "admin"), booking.GetOwnerStatsHandler)
+		v1.GET("/owner/venues/:id/stats", Authorize(policy.VenueViewRevenue), booking.GetOwnerStatsHandler)
 
-		v1.GET("/admin/stats/by-venue", AuthMiddleware("admin"), booking.GetGroupedStatsHandler)
+		v1.GET("/admin/stats/by-venue", Authorize(policy.StatsPlatform), booking.GetGroupedStatsHandler)
 		// We will add the chat and profile routes here once we build their handlers.
 
-		v1.POST("/bookings/block", AuthMiddleware("owner", "admin"), booking.BlockSlotHandler)
+		v1.POST("/bookings/block", Authorize(policy.BookingBlock), booking.BlockSlotHandler)
 
-		v1.GET("/owner/stats/by-venue", AuthMiddleware("owner", "admin"), booking.GetOwnerGroupedStatsHandler)
-		v1.POST("/teams/:id/chat", AuthMiddleware("player", "owner", "admin"), team.PostMessageHandler)
-		v1.GET("/teams/:id/chat", AuthMiddleware("player", "owner", "admin"), team.GetTeamMessagesHandler)
+		v1.GET("/owner/stats/by-venue", Authorize(policy.StatsOwnVenues), booking.GetOwnerGroupedStatsHandler)
+		v1.POST("/teams/:id/chat", Authorize(policy.TeamChatPost), team.PostMessageHandler)
+		v1.GET("/teams/:id/chat", Authorize(policy.TeamChatRead), team.GetTeamMessagesHandler)
 
-		v1.GET("/profile/me", AuthMiddleware("player", "owner", "admin"), user.GetProfileHandler)
+		v1.GET("/profile/me", Authorize(policy.ProfileView), user.GetProfileHandler)
 
-		v1.PATCH("/profile/me", AuthMiddleware("player", "owner", "admin"), user.UpdateProfileHandler)
+		v1.PATCH("/profile/me", Authorize(policy.ProfileEdit), user.UpdateProfileHandler)
+		v1.POST("/logout/all", Authorize(policy.SessionEndAll), user.LogoutAllHandler)
+		v1.POST("/verify-email/resend", Authorize(policy.EmailResend), user.ResendVerificationHandler)
+		v1.POST("/profile/password", Authorize(policy.PasswordChange), user.ChangePasswordHandler)
+		v1.POST("/phone/verify/send", Authorize(policy.PhoneVerify), user.SendPhoneVerificationHandler)
+		v1.POST("/phone/verify", Authorize(policy.PhoneVerify), user.VerifyPhoneHandler)
 		v1.GET("/venues/:id/reviews", venue.GetReviewsHandler) // Anyone can read reviews
 
-		v1.POST("/venues/:id/reviews", AuthMiddleware("player", "owner", "admin"), venue.CreateReviewHandler)
-
-		v1.PUT("/venues/:id", AuthMiddleware("owner", "admin"), venue.UpdateVenueHandler)
-
-		v1.POST("/profile/avatar", AuthMiddleware("player", "owner", "admin"), user.UploadProfilePicHandler)
-		v1.GET("/notifications", AuthMiddleware("player", "owner", "admin"), notification.GetMyNotificationsHandler)
-		v1.PATCH("/notifications/:id/read", AuthMiddleware("player", "owner", "admin"), notification.MarkReadHandler)
+		v1.POST("/venues/:id/reviews", Authorize(policy.ReviewCreate), venue.CreateReviewHandler)
+		v1.PATCH("/reviews/:id", Authorize(policy.ReviewEdit), venue.UpdateReviewHandler)
+		v1.DELETE("/reviews/:id", Authorize(policy.ReviewDelete), venue.DeleteReviewHandler)
+		v1.POST("/reviews/:id/report", Authorize(policy.ReviewReport), venue.ReportReviewHandler)
+		v1.POST("/reviews/:id/photos", Authorize(policy.ReviewPhotoAdd), venue.UploadReviewPhotoHandler)
+		v1.DELETE("/reviews/:id/photos/:photo_id", Authorize(policy.ReviewPhotoDelete), venue.DeleteReviewPhotoHandler)
+		v1.PUT("/reviews/:id/reply", Authorize(policy.ReviewReply), venue.ReplyToReviewHandler)
+		v1.DELETE("/reviews/:id/reply", Authorize(policy.ReviewReply), venue.DeleteReviewReplyHandler)
+
+		v1.PUT("/venues/:id", Authorize(policy.VenueEdit), venue.UpdateVenueHandler)
+		v1.PATCH("/venues/:id", Authorize(policy.VenueEdit), venue.PatchVenueHandler)
+		v1.DELETE("/venues/:id", Authorize(policy.VenueManage), venue.ArchiveVenueHandler)
+		v1.PUT("/venues/:id/lifecycle", Authorize(policy.VenueManage), venue.UpdateVenueLifecycleHandler)
+		v1.GET("/venues/:id/revision", Authorize(policy.VenueEdit), venue.GetPendingRevisionHandler)
+
+		v1.POST("/profile/avatar", Authorize(policy.ProfileAvatar), user.UploadProfilePicHandler)
+		v1.GET("/notifications", Authorize(policy.NotificationList), notification.GetMyNotificationsHandler)
+		v1.PATCH("/notifications/:id/read", Authorize(policy.NotificationRead), notification.MarkReadHandler)
 		v1.GET("/venues/:id/slots", booking.GetBookedSlotsHandler)
 
+		// === League Routes ===
+		v1.GET("/leagues", league.GetLeaguesHandler)
+		v1.GET("/leagues/:id", league.GetLeagueHandler)
+		v1.GET("/seasons/:id/fixtures", league.GetFixturesHandler)
+		v1.GET("/seasons/:id/standings", league.GetStandingsHandler) // Public standings table
+		v1.POST("/leagues", Authorize(policy.LeagueCreate), league.CreateLeagueHandler)
+		v1.POST("/leagues/:id/seasons", Authorize(policy.SeasonCreate), league.CreateSeasonHandler)
+		v1.POST("/seasons/:id/teams", Authorize(policy.SeasonAddTeam), league.AddSeasonTeamHandler)
+		v1.POST("/seasons/:id/fixtures", Authorize(policy.FixtureCreate), league.CreateFixtureHandler)
+		v1.POST("/fixtures/:id/result", Authorize(policy.FixtureSubmitResult), league.SubmitResultHandler)
+		v1.POST("/fixtures/:id/result/confirm", Authorize(policy.FixtureConfirmResult), league.ConfirmResultHandler)
+
+		// === Player Ratings ===
+		v1.GET("/players", Authorize(policy.PlayerSearch), rating.SearchPlayersHandler)
+		v1.GET("/players/:id/ratings/history", rating.GetRatingHistoryHandler)
+
 	}
 }
@@ -0,0 +1,436 @@
+// api/router_test.go
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JkD004/playarena-backend/jwtkeys"
+	"github.com/JkD004/playarena-backend/policy"
+	"github.com/JkD004/playarena-backend/user"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "router-test-secret"
+
+// The fake world the policy checks run against: user 1 owns, staffs and
+// belongs to everything with ID 7; user 8 is staff at venue 7 allowed only to
+// view bookings; ID 404 does not exist.
+const (
+	ownerID    int64 = 1
+	strangerID int64 = 2
+	adminID    int64 = 99
+
+	// Accounts whose live state differs from their token
+	promotedID   int64 = 3 // token says player, now an owner
+	suspendedID  int64 = 4
+	revokedID    int64 = 5
+	unverifiedID int64 = 6 // hasn't confirmed their email
+
+	staffID int64 = 8 // venue staff with only staffGrant
+
+	ownedID   = "7"
+	missingID = "404"
+
+	staffGrant = policy.StaffViewBookings
+)
+
+// routeCase is one route of SetupRoutes and who may get past its guard
+type routeCase struct {
+	method, path string
+	perm         policy.Permission // "" for public routes
+	roles        []string          // roles that pass on their own resource
+	stranger     bool              // a player unrelated to the resource passes
+	admin        bool              // an admin unrelated to the resource passes
+	staff        bool              // the view_bookings-only staff member passes
+}
+
+var allRoles = []string{"player", "owner", "admin"}
+
+func public(method, path string) routeCase {
+	return routeCase{method: method, path: path}
+}
+
+// forAdmin routes are limited to the admin role
+func forAdmin(method, path string, perm policy.Permission) routeCase {
+	return routeCase{method, path, perm, []string{"admin"}, false, true, false}
+}
+
+// forAnyone routes only need a valid token
+func forAnyone(method, path string, perm policy.Permission) routeCase {
+	return routeCase{method, path, perm, allRoles, true, true, true}
+}
+
+// forOwnerRole routes need the owner or admin role
+func forOwnerRole(method, path string, perm policy.Permission) routeCase {
+	return routeCase{method, path, perm, []string{"owner", "admin"}, false, true, false}
+}
+
+// forRelated routes need the user to own, staff or belong to the resource;
+// adminOverride lets admins act on anyone's resource
+func forRelated(method, path string, perm policy.Permission, adminOverride bool) routeCase {
+	return routeCase{method, path, perm, allRoles, false, adminOverride, false}
+}
+
+// forStaff routes are forRelated venue routes that staffGrant also opens
+func forStaff(method, path string, perm policy.Permission) routeCase {
+	return routeCase{method, path, perm, allRoles, false, true, true}
+}
+
+var routeTable = []routeCase{
+	// Public
+	public("GET", "/.well-known/jwks.json"),
+	public("POST", "/api/v1/register"),
+	public("POST", "/api/v1/login"),
+	public("POST", "/api/v1/token/refresh"),
+	public("POST", "/api/v1/logout"),
+	public("GET", "/api/v1/verify-email"),
+	public("POST", "/api/v1/password/forgot"),
+	public("POST", "/api/v1/password/reset"),
+	public("POST", "/api/v1/login/otp/send"),
+	public("POST", "/api/v1/login/otp"),
+	public("GET", "/api/v1/venues"),
+	public("GET", "/api/v1/venues/available"),
+	public("GET", "/api/v1/venues/:id"),
+	public("GET", "/api/v1/venues/:id/photos"),
+	public("GET", "/api/v1/venues/:id/reviews"),
+	public("GET", "/api/v1/venues/:id/slots"),
+	public("GET", "/api/v1/sports"),
+	public("GET", "/api/v1/amenities"),
+	public("GET", "/api/v1/leagues"),
+	public("GET", "/api/v1/leagues/:id"),
+	public("GET", "/api/v1/seasons/:id/fixtures"),
+	public("GET", "/api/v1/seasons/:id/standings"),
+	public("GET", "/api/v1/players/:id/ratings/history"),
+
+	// Admin
+	forAdmin("GET", "/api/v1/admin/venues", policy.VenueListAll),
+	forAdmin("PATCH", "/api/v1/admin/venues/:id/status", policy.VenueDecide),
+	forAdmin("GET", "/api/v1/admin/bookings", policy.BookingListAll),
+	forAdmin("GET", "/api/v1/admin/reviews/reports", policy.ReviewModerate),
+	forAdmin("PATCH", "/api/v1/admin/reviews/:id", policy.ReviewModerate),
+	forAdmin("DELETE", "/api/v1/admin/reviews/:id", policy.ReviewModerate),
+	forAdmin("GET", "/api/v1/admin/venue-revisions", policy.RevisionDecide),
+	forAdmin("PATCH", "/api/v1/admin/venue-revisions/:id", policy.RevisionDecide),
+	forAdmin("GET", "/api/v1/admin/sports", policy.TaxonomyManage),
+	forAdmin("POST", "/api/v1/admin/sports", policy.TaxonomyManage),
+	forAdmin("PATCH", "/api/v1/admin/sports/:id", policy.TaxonomyManage),
+	forAdmin("GET", "/api/v1/admin/amenities", policy.TaxonomyManage),
+	forAdmin("POST", "/api/v1/admin/amenities", policy.TaxonomyManage),
+	forAdmin("PATCH", "/api/v1/admin/amenities/:id", policy.TaxonomyManage),
+	forAdmin("GET", "/api/v1/admin/stats/by-venue", policy.StatsPlatform),
+	forAdmin("PATCH", "/api/v1/admin/users/:id/status", policy.UserManage),
+	forAdmin("PATCH", "/api/v1/admin/users/:id/role", policy.UserManage),
+
+	// Venues
+	forAnyone("POST", "/api/v1/venues", policy.VenueCreate),
+	forAnyone("GET", "/api/v1/venues/mine", policy.VenueListOwn),
+	forRelated("PUT", "/api/v1/venues/:id", policy.VenueEdit, true),
+	forRelated("PATCH", "/api/v1/venues/:id", policy.VenueEdit, true),
+	forRelated("DELETE", "/api/v1/venues/:id", policy.VenueManage, true),
+	forRelated("PUT", "/api/v1/venues/:id/lifecycle", policy.VenueManage, true),
+	forRelated("GET", "/api/v1/venues/:id/revision", policy.VenueEdit, true),
+	forRelated("GET", "/api/v1/venues/:id/history", policy.VenueEdit, true),
+	forRelated("POST", "/api/v1/venues/:id/resubmit", policy.VenueResubmit, false),
+	forRelated("POST", "/api/v1/venues/:id/photos", policy.VenueEdit, true),
+	forRelated("PUT", "/api/v1/venues/:id/photos/order", policy.VenueEdit, true),
+	forRelated("PUT", "/api/v1/venues/:id/cover", policy.VenueEdit, true),
+	forRelated("DELETE", "/api/v1/photos/:id", policy.PhotoEdit, true),
+	forRelated("PATCH", "/api/v1/photos/:id", policy.PhotoEdit, true),
+	forStaff("GET", "/api/v1/venues/:id/bookings", policy.VenueViewBookings),
+	forRelated("GET", "/api/v1/owner/venues/:id/stats", policy.VenueViewRevenue, true),
+	forOwnerRole("GET", "/api/v1/owner/stats/by-venue", policy.StatsOwnVenues),
+
+	// Venue staff
+	forRelated("GET", "/api/v1/venues/:id/staff", policy.VenueManage, true),
+	forRelated("POST", "/api/v1/venues/:id/staff", policy.VenueManage, true),
+	forRelated("GET", "/api/v1/venues/:id/staff/actions", policy.VenueManage, true),
+	forRelated("PATCH", "/api/v1/venues/:id/staff/:user_id", policy.VenueManage, true),
+	forRelated("DELETE", "/api/v1/venues/:id/staff/:user_id", policy.VenueManage, true),
+	forAnyone("GET", "/api/v1/staff/venues", policy.StaffListOwn),
+	forAnyone("PATCH", "/api/v1/staff/venues/:id", policy.StaffRespond),
+
+	// Bookings
+	forAnyone("POST", "/api/v1/bookings", policy.BookingCreate),
+	forAnyone("GET", "/api/v1/bookings/mine", policy.BookingListOwn),
+	forAnyone("POST", "/api/v1/bookings/block", policy.BookingBlock),
+	forRelated("POST", "/api/v1/bookings/:id/pay", policy.BookingPay, false),
+	forRelated("PATCH", "/api/v1/bookings/:id/cancel", policy.BookingCancel, false),
+	forRelated("POST", "/api/v1/bookings/:id/check-in", policy.BookingCheckIn, true),
+
+	// Teams
+	forAnyone("POST", "/api/v1/teams", policy.TeamCreate),
+	forAnyone("GET", "/api/v1/teams/mine", policy.TeamListOwn),
+	forAnyone("PATCH", "/api/v1/teams/:id/status", policy.TeamRespond),
+	forRelated("POST", "/api/v1/teams/:id/invite", policy.TeamInvite, false),
+	forRelated("GET", "/api/v1/teams/:id/members", policy.TeamViewMembers, false),
+	forRelated("POST", "/api/v1/teams/:id/chat", policy.TeamChatPost, false),
+	forRelated("GET", "/api/v1/teams/:id/chat", policy.TeamChatRead, false),
+
+	// Profile
+	forAnyone("GET", "/api/v1/profile/me", policy.ProfileView),
+	forAnyone("PATCH", "/api/v1/profile/me", policy.ProfileEdit),
+	forAnyone("POST", "/api/v1/profile/avatar", policy.ProfileAvatar),
+	forAnyone("POST", "/api/v1/logout/all", policy.SessionEndAll),
+	forAnyone("POST", "/api/v1/verify-email/resend", policy.EmailResend),
+	forAnyone("POST", "/api/v1/profile/password", policy.PasswordChange),
+	forAnyone("POST", "/api/v1/phone/verify/send", policy.PhoneVerify),
+	forAnyone("POST", "/api/v1/phone/verify", policy.PhoneVerify),
+
+	// Reviews
+	forAnyone("POST", "/api/v1/venues/:id/reviews", policy.ReviewCreate),
+	forRelated("PATCH", "/api/v1/reviews/:id", policy.ReviewEdit, false),
+	forRelated("DELETE", "/api/v1/reviews/:id", policy.ReviewDelete, false),
+	forAnyone("POST", "/api/v1/reviews/:id/report", policy.ReviewReport),
+	forRelated("POST", "/api/v1/reviews/:id/photos", policy.ReviewPhotoAdd, false),
+	forRelated("DELETE", "/api/v1/reviews/:id/photos/:photo_id", policy.ReviewPhotoDelete, true),
+	forRelated("PUT", "/api/v1/reviews/:id/reply", policy.ReviewReply, true),
+	forRelated("DELETE", "/api/v1/reviews/:id/reply", policy.ReviewReply, true),
+
+	// Notifications
+	forAnyone("GET", "/api/v1/notifications", policy.NotificationList),
+	forRelated("PATCH", "/api/v1/notifications/:id/read", policy.NotificationRead, false),
+
+	// Leagues
+	forAnyone("POST", "/api/v1/leagues", policy.LeagueCreate),
+	forAnyone("POST", "/api/v1/leagues/:id/seasons", policy.SeasonCreate),
+	forAnyone("POST", "/api/v1/seasons/:id/teams", policy.SeasonAddTeam),
+	forAnyone("POST", "/api/v1/seasons/:id/fixtures", policy.FixtureCreate),
+	forAnyone("POST", "/api/v1/fixtures/:id/result", policy.FixtureSubmitResult),
+	forAnyone("POST", "/api/v1/fixtures/:id/result/confirm", policy.FixtureConfirmResult),
+
+	// Players
+	forAnyone("GET", "/api/v1/players", policy.PlayerSearch),
+}
+
+// testRouter builds the real route table with the policy pointed at the fake
+// world. Handlers have no database, so they panic; the outer middleware
+// recovers and records whether the request got past the guard.
+func testRouter(t *testing.T) (*gin.Engine, *bool) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", testSecret)
+
+	passed := new(bool)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		defer func() {
+			_, *passed = c.Get("userID")
+			if recover() != nil {
+				c.AbortWithStatus(http.StatusTeapot)
+			}
+		}()
+		c.Next()
+	})
+	SetupRoutes(r)
+
+	// SetupRoutes registers the real checks, so override them afterwards
+	load := func(id int64) (*policy.Resource, error) {
+		if id == 404 {
+			return nil, policy.ErrNotFound
+		}
+		return &policy.Resource{OwnerID: ownerID, VenueID: id, TeamID: id}, nil
+	}
+	for _, kind := range []string{policy.ResourceVenue, policy.ResourcePhoto, policy.ResourceBooking,
+		policy.ResourceReview, policy.ResourceNotification, policy.ResourceTeam} {
+		policy.RegisterLoader(kind, load)
+	}
+	policy.SetVenueAccess(func(venueID, userID int64, staffPermission string) (bool, error) {
+		if venueID != 7 {
+			return false, nil
+		}
+		if userID == staffID {
+			// Staff never count as the owner, and only have their one grant
+			return staffPermission == staffGrant, nil
+		}
+		return userID == ownerID, nil
+	})
+	policy.SetTeamMembership(func(teamID, userID int64) (bool, error) {
+		return teamID == 7 && userID == ownerID, nil
+	})
+	resolveAuth = func(claims *user.Claims) (*user.AuthState, error) {
+		switch claims.UserID {
+		case promotedID:
+			return &user.AuthState{UserID: promotedID, Role: "owner", Status: user.StatusActive, EmailVerified: true}, nil
+		case suspendedID:
+			return nil, user.ErrAccountSuspended
+		case revokedID:
+			return nil, user.ErrTokenRevoked
+		case unverifiedID:
+			return &user.AuthState{UserID: unverifiedID, Role: claims.Role, Status: user.StatusActive}, nil
+		}
+		return &user.AuthState{UserID: claims.UserID, Role: claims.Role, Status: user.StatusActive, EmailVerified: true}, nil
+	}
+	return r, passed
+}
+
+func testToken(t *testing.T, userID int64, role string) string {
+	t.Helper()
+	claims := &user.Claims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	signed, err := jwtkeys.Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// fillPath replaces the route's :id with id and any other parameter with 5
+func fillPath(path, id string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		switch {
+		case p == ":id":
+			parts[i] = id
+		case strings.HasPrefix(p, ":"):
+			parts[i] = "5"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func serve(r *gin.Engine, method, path, token string) int {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRouteTableCoversEveryRoute(t *testing.T) {
+	r, _ := testRouter(t)
+
+	registered := map[string]bool{}
+	for _, ri := range r.Routes() {
+		key := ri.Method + " " + ri.Path
+		registered[key] = true
+	}
+	listed := map[string]bool{}
+	for _, rc := range routeTable {
+		key := rc.method + " " + rc.path
+		if listed[key] {
+			t.Errorf("%s is listed twice", key)
+		}
+		listed[key] = true
+		if !registered[key] {
+			t.Errorf("%s is listed but not registered", key)
+		}
+	}
+	for key := range registered {
+		if !listed[key] {
+			t.Errorf("%s is registered but has no test case", key)
+		}
+	}
+}
+
+func TestRoutePermissions(t *testing.T) {
+	gin.DefaultErrorWriter = io.Discard
+	r, passed := testRouter(t)
+
+	for _, rc := range routeTable {
+		rc := rc
+		t.Run(rc.method+" "+rc.path, func(t *testing.T) {
+			path := fillPath(rc.path, ownedID)
+
+			if rc.perm == "" {
+				if code := serve(r, rc.method, path, ""); code == http.StatusUnauthorized || code == http.StatusForbidden {
+					t.Errorf("public route answered %d without a token", code)
+				}
+				return
+			}
+
+			if code := serve(r, rc.method, path, ""); code != http.StatusUnauthorized {
+				t.Errorf("no token: got %d, want 401", code)
+			}
+
+			check := func(who string, userID int64, role string, want bool) {
+				t.Helper()
+				*passed = false
+				code := serve(r, rc.method, path, testToken(t, userID, role))
+				if *passed != want {
+					t.Errorf("%s (%s): passed guard = %v (status %d), want %v", who, role, *passed, code, want)
+				}
+				if !want && code != http.StatusForbidden {
+					t.Errorf("%s (%s): got %d, want 403", who, role, code)
+				}
+			}
+			for _, role := range allRoles {
+				check("related user", ownerID, role, contains(rc.roles, role))
+			}
+			check("stranger", strangerID, "player", rc.stranger)
+			check("unrelated admin", adminID, "admin", rc.admin)
+			check("view_bookings staff", staffID, "player", rc.staff)
+
+			if policy.RuleFor(rc.perm).Resource != "" {
+				missing := fillPath(rc.path, missingID)
+				if code := serve(r, rc.method, missing, testToken(t, ownerID, "player")); code != http.StatusNotFound {
+					t.Errorf("missing resource: got %d, want 404", code)
+				}
+				bad := fillPath(rc.path, "abc")
+				if code := serve(r, rc.method, bad, testToken(t, ownerID, "player")); code != http.StatusBadRequest {
+					t.Errorf("invalid id: got %d, want 400", code)
+				}
+			}
+		})
+	}
+}
+
+func TestGuardUsesLiveAccountState(t *testing.T) {
+	gin.DefaultErrorWriter = io.Discard
+	r, passed := testRouter(t)
+	const path = "/api/v1/owner/stats/by-venue" // owner and admin roles only
+
+	*passed = false
+	serve(r, "GET", path, testToken(t, promotedID, "player"))
+	if !*passed {
+		t.Error("promoted user: the live owner role was not used")
+	}
+	if code := serve(r, "GET", path, testToken(t, suspendedID, "owner")); code != http.StatusForbidden {
+		t.Errorf("suspended user: got %d, want 403", code)
+	}
+	if code := serve(r, "GET", path, testToken(t, revokedID, "owner")); code != http.StatusUnauthorized {
+		t.Errorf("revoked token: got %d, want 401", code)
+	}
+}
+
+func TestUnverifiedEmailCannotBookOrListVenues(t *testing.T) {
+	gin.DefaultErrorWriter = io.Discard
+	r, passed := testRouter(t)
+	token := testToken(t, unverifiedID, "player")
+
+	for _, path := range []string{"/api/v1/bookings", "/api/v1/venues"} {
+		*passed = false
+		if code := serve(r, "POST", path, token); code != http.StatusForbidden || *passed {
+			t.Errorf("POST %s: got %d (passed guard = %v), want 403", path, code, *passed)
+		}
+	}
+
+	*passed = false
+	serve(r, "GET", "/api/v1/bookings/mine", token)
+	if !*passed {
+		t.Error("GET /bookings/mine: an unverified user should still get in")
+	}
+}
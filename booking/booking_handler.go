@@ -2,8 +2,11 @@
 package booking
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
+	"github.com/JkD004/playarena-backend/policy"
 	"github.com/JkD004/playarena-backend/venue"
 	"github.com/gin-gonic/gin"
 )
@@ -86,8 +89,8 @@ func GetVenueBookingsHandler(c *gin.Context) {
 		return
 	}
 
-	// TODO: Verify owner (from c.MustGet("userID")) owns this venueID
-	
+	// Access is checked by the route policy (policy.VenueViewBookings)
+
 	bookings, err := GetBookingsForVenue(venueID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch bookings"})
@@ -100,27 +103,21 @@ func GetVenueBookingsHandler(c *gin.Context) {
 
 // GetOwnerStatsHandler handles fetching all stats for the logged-in owner
 func GetOwnerStatsHandler(c *gin.Context) {
-	userID := c.MustGet("userID").(int64)
-	userRole := c.MustGet("userRole").(string)
-
 	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
 		return
 	}
-	
-	// --- SECURITY CHECK ---
-	// If not admin, ensure the user actually owns this venue
-	if userRole != "admin" {
-		// We use the function exported from the venue package
-		if err := venue.VerifyVenueOwnership(venueID, userID); err != nil {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to view these stats"})
-			return
-		}
+	// Access is checked by the route policy (policy.VenueViewRevenue)
+
+	// Stats are keyed by the venue's owner, whoever is looking at them
+	v, err := venue.FindVenueByID(venueID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+		return
 	}
-	// ----------------------
 
-	stats, err := GetStatisticsForOwner(userID, venueID)
+	stats, err := GetStatisticsForOwner(v.OwnerID, venueID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not calculate statistics"})
 		return
@@ -159,6 +156,18 @@ func BlockSlotHandler(c *gin.Context) {
 	}
 
 	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	// The venue comes from the body, so the policy is checked here
+	subject := policy.Subject{UserID: userID, Role: userRole}
+	if err := policy.Can(subject, policy.VenueBlockSlots, req.VenueID); err != nil {
+		if errors.Is(err, policy.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to block slots at this venue"})
+		return
+	}
 
 	// Call service (we reuse CreateNewBooking but with a flag or logic)
     // Ideally, we create a specific service function for this.
@@ -170,9 +179,39 @@ func BlockSlotHandler(c *gin.Context) {
 		return
 	}
 
+	venue.RecordStaffAction(req.VenueID, userID, userRole, "slot_blocked",
+		req.StartTime.Format(time.RFC3339)+" - "+req.EndTime.Format(time.RFC3339))
+
 	c.JSON(http.StatusCreated, gin.H{"message": "Slot blocked successfully"})
 }
 
+// CheckInBookingHandler handles POST /api/v1/bookings/:id/check-in
+// (venue owner, admin, or staff with the check_in permission)
+func CheckInBookingHandler(c *gin.Context) {
+	bookingID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	if err := CheckInBooking(bookingID, userID, userRole); err != nil {
+		switch {
+		case errors.Is(err, ErrBookingNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrNotCheckInable), errors.Is(err, ErrCheckInTooEarly),
+			errors.Is(err, ErrBookingEnded), errors.Is(err, ErrAlreadyCheckedIn):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check in booking"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Booking checked in"})
+}
+
 
 // booking/booking_handler.go
 
@@ -198,10 +237,14 @@ func ProcessPaymentHandler(c *gin.Context) {
 		return
 	}
 
-	// TODO: You might want to check if the user owns this booking
+	// Only the booking's owner gets here (policy.BookingPay)
 
 	err = ProcessPayment(bookingID)
 	if err != nil {
+		if errors.Is(err, ErrNotAwaitingPayment) || errors.Is(err, ErrHoldExpired) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Payment failed"})
 		return
 	}
@@ -234,4 +277,41 @@ func GetBookedSlotsHandler(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, slots)
+}
+
+// SearchAvailableVenuesHandler handles
+// GET /api/v1/venues/available?sport=football&start=RFC3339&end=RFC3339[&near=lat,lng&radius_km=]
+// Any other GET /venues search filter can be combined with the time window.
+func SearchAvailableVenuesHandler(c *gin.Context) {
+	start, err := time.Parse(time.RFC3339, c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start is required (RFC3339)"})
+		return
+	}
+	end, err := time.Parse(time.RFC3339, c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end is required (RFC3339)"})
+		return
+	}
+	if c.Query("sport") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sport is required"})
+		return
+	}
+
+	params, err := venue.ParseVenueSearchParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	venues, next, err := SearchAvailableVenues(params, start, end)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if next != "" {
+		c.Header("X-Next-Cursor", next)
+	}
+	c.JSON(http.StatusOK, venues)
 }
\ No newline at end of file
@@ -13,9 +13,16 @@ type Booking struct {
 	EndTime       time.Time `json:"end_time"`
 	TotalPrice    float64   `json:"total_price"`
 	Status        string    `json:"status"`
+	Kind          string    `json:"kind"` // KindBooking or KindBlock
 	CreatedAt     time.Time `json:"created_at"`
 }
 
+// Booking kinds: a player's booking, or a slot blocked by the venue
+const (
+	KindBooking = "booking"
+	KindBlock   = "block"
+)
+
 // ... (keep CreateBookingRequest struct)
 
 // Add a struct for the request body, as users won't send everything
@@ -28,18 +35,19 @@ type CreateBookingRequest struct {
 
 // AdminBookingView includes venue name and user name
 type AdminBookingView struct {
-	BookingID     int64     `json:"booking_id"`
-	VenueID       int64     `json:"venue_id"`
-	VenueName     string    `json:"venue_name"`
-	SportCategory string    `json:"sport_category"`
-	UserID        int64     `json:"user_id"`
-	UserFirstName string    `json:"user_first_name"` // <-- ADD THIS
-	UserLastName  string    `json:"user_last_name"`  // <-- ADD THIS
-	StartTime     time.Time `json:"start_time"`
-	EndTime       time.Time `json:"end_time"`
-	TotalPrice    float64   `json:"total_price"`
-	Status        string    `json:"status"`
-	UserPhone     string    `json:"user_phone"`
+	BookingID     int64      `json:"booking_id"`
+	VenueID       int64      `json:"venue_id"`
+	VenueName     string     `json:"venue_name"`
+	SportCategory string     `json:"sport_category"`
+	UserID        int64      `json:"user_id"`
+	UserFirstName string     `json:"user_first_name"` // <-- ADD THIS
+	UserLastName  string     `json:"user_last_name"`  // <-- ADD THIS
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       time.Time  `json:"end_time"`
+	TotalPrice    float64    `json:"total_price"`
+	Status        string     `json:"status"`
+	UserPhone     string     `json:"user_phone"`
+	CheckedInAt   *time.Time `json:"checked_in_at,omitempty"`
 }
 
 // OwnerStats defines the data for the owner's dashboard
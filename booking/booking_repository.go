@@ -13,9 +13,12 @@ import (
 // CreateBooking inserts a new booking into the database
 func CreateBooking(booking *Booking) error {
 	query := `
-		INSERT INTO bookings (user_id, venue_id, start_time, end_time, total_price, status)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO bookings (user_id, venue_id, start_time, end_time, total_price, status, kind)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
+	if booking.Kind == "" {
+		booking.Kind = KindBooking
+	}
 	result, err := db.DB.Exec(query,
 		booking.UserID,
 		booking.VenueID,
@@ -23,6 +26,7 @@ func CreateBooking(booking *Booking) error {
 		booking.EndTime,
 		booking.TotalPrice,
 		booking.Status,
+		booking.Kind,
 	)
 	if err != nil {
 		log.Println("Error inserting booking:", err)
@@ -34,18 +38,18 @@ func CreateBooking(booking *Booking) error {
 	return nil
 }
 
-// IsSlotAvailable checks for overlapping confirmed bookings
+// IsSlotAvailable checks for overlapping confirmed bookings, blocks and live holds
 func IsSlotAvailable(venueID int64, startTime, endTime time.Time) (bool, error) {
 	var count int
 	query := `
 		SELECT COUNT(*) FROM bookings
 		WHERE venue_id = ?
-		AND status = 'confirmed' 
+		AND (status = 'confirmed' OR (status = 'pending' AND created_at > ?))
 		AND start_time < ?
 		AND end_time > ?
 	`
 
-	err := db.DB.QueryRow(query, venueID, endTime, startTime).Scan(&count)
+	err := db.DB.QueryRow(query, venueID, HoldCutoff(), endTime, startTime).Scan(&count)
 	if err != nil {
 		log.Println("Error checking slot availability:", err)
 		return false, err
@@ -61,7 +65,7 @@ func FindBookingsByUserID(userID int64) ([]Booking, error) {
 		SELECT 
 			b.id, b.user_id, b.venue_id, 
 			v.name, v.sport_category, 
-			b.start_time, b.end_time, b.total_price, b.status, b.created_at
+			b.start_time, b.end_time, b.total_price, b.status, b.kind, b.created_at
 		FROM bookings b
 		JOIN venues v ON b.venue_id = v.id
 		WHERE b.user_id = ?
@@ -88,6 +92,7 @@ func FindBookingsByUserID(userID int64) ([]Booking, error) {
 			&booking.EndTime,
 			&booking.TotalPrice,
 			&booking.Status,
+			&booking.Kind,
 			&booking.CreatedAt,
 		); err != nil {
 			log.Println("Error scanning booking row:", err)
@@ -133,7 +138,7 @@ func FindBookingsByVenueID(venueID int64) ([]AdminBookingView, error) {
 		SELECT 
 			b.id, b.venue_id, v.name, v.sport_category, b.user_id, 
 			u.first_name, u.last_name, COALESCE(u.phone, 'N/A'),
-			b.start_time, b.end_time, b.total_price, b.status
+			b.start_time, b.end_time, b.total_price, b.status, b.checked_in_at
 		FROM bookings b
 		JOIN venues v ON b.venue_id = v.id
 		JOIN users u ON b.user_id = u.id 
@@ -150,6 +155,7 @@ func FindBookingsByVenueID(venueID int64) ([]AdminBookingView, error) {
 	var bookings []AdminBookingView
 	for rows.Next() {
 		var b AdminBookingView
+		var checkedIn sql.NullTime
 		if err := rows.Scan(
 			&b.BookingID,
 			&b.VenueID,
@@ -163,10 +169,14 @@ func FindBookingsByVenueID(venueID int64) ([]AdminBookingView, error) {
 			&b.EndTime,
 			&b.TotalPrice,
 			&b.Status,
+			&checkedIn,
 		); err != nil {
 			log.Println("Error scanning booking row:", err)
 			continue
 		}
+		if checkedIn.Valid {
+			b.CheckedInAt = &checkedIn.Time
+		}
 		bookings = append(bookings, b)
 	}
 
@@ -182,7 +192,7 @@ func FindAllBookings() ([]AdminBookingView, error) {
 		SELECT 
 			b.id, b.venue_id, v.name, v.sport_category, b.user_id, 
 			u.first_name, u.last_name, COALESCE(u.phone, 'N/A'),
-			b.start_time, b.end_time, b.total_price, b.status
+			b.start_time, b.end_time, b.total_price, b.status, b.checked_in_at
 		FROM bookings b
 		JOIN venues v ON b.venue_id = v.id
 		JOIN users u ON b.user_id = u.id 
@@ -198,6 +208,7 @@ func FindAllBookings() ([]AdminBookingView, error) {
 	var bookings []AdminBookingView
 	for rows.Next() {
 		var b AdminBookingView
+		var checkedIn sql.NullTime
 		if err := rows.Scan(
 			&b.BookingID,
 			&b.VenueID,
@@ -211,10 +222,14 @@ func FindAllBookings() ([]AdminBookingView, error) {
 			&b.EndTime,
 			&b.TotalPrice,
 			&b.Status,
+			&checkedIn,
 		); err != nil {
 			log.Println("Error scanning booking row:", err)
 			continue
 		}
+		if checkedIn.Valid {
+			b.CheckedInAt = &checkedIn.Time
+		}
 		bookings = append(bookings, b)
 	}
 
@@ -425,28 +440,52 @@ func GetOwnerVenueStatsGrouped(ownerID int64) ([]VenueStats, error) {
 	return statsList, nil
 }
 
-// ConfirmBookingPayment updates status to 'confirmed' after payment
-func ConfirmBookingPayment(bookingID int64) error {
-	query := `UPDATE bookings SET status = 'confirmed' WHERE id = ?`
-	_, err := db.DB.Exec(query, bookingID)
+// ConfirmBookingPayment updates status to 'confirmed' after payment.
+// Only pending bookings created after holdCutoff (live holds, which still
+// keep their slot) are confirmed; it reports false otherwise.
+func ConfirmBookingPayment(bookingID int64, holdCutoff time.Time) (bool, error) {
+	query := `UPDATE bookings SET status = 'confirmed' WHERE id = ? AND status = 'pending' AND created_at > ?`
+	result, err := db.DB.Exec(query, bookingID, holdCutoff)
 	if err != nil {
 		log.Println("Error confirming payment:", err)
-		return err
+		return false, err
 	}
-	return nil
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// MarkBookingCheckedIn records a check-in; it reports false if the booking
+// was already checked in or is not confirmed
+func MarkBookingCheckedIn(bookingID, checkedInBy int64) (bool, error) {
+	query := `
+		UPDATE bookings SET checked_in_at = CURRENT_TIMESTAMP, checked_in_by = ?
+		WHERE id = ? AND status = 'confirmed' AND checked_in_at IS NULL`
+	result, err := db.DB.Exec(query, checkedInBy, bookingID)
+	if err != nil {
+		log.Println("Error checking in booking:", err)
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
 // FindBookingByID fetches a single booking by its ID
 func FindBookingByID(bookingID int64) (*Booking, error) {
 	query := `
-		SELECT id, user_id, venue_id, start_time, end_time, total_price, status, created_at
+		SELECT id, user_id, venue_id, start_time, end_time, total_price, status, kind, created_at
 		FROM bookings
 		WHERE id = ?
 	`
 	var b Booking
 	err := db.DB.QueryRow(query, bookingID).Scan(
 		&b.ID, &b.UserID, &b.VenueID, &b.StartTime, &b.EndTime, 
-		&b.TotalPrice, &b.Status, &b.CreatedAt,
+		&b.TotalPrice, &b.Status, &b.Kind, &b.CreatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -455,17 +494,17 @@ func FindBookingByID(bookingID int64) (*Booking, error) {
 }
 
 // --- FIX: Simplified Query for GetBookedSlotsForDate ---
-// GetBookedSlotsForDate fetches confirmed bookings for a specific venue and date
+// GetBookedSlotsForDate fetches confirmed bookings and live holds for a specific venue and date
 func GetBookedSlotsForDate(venueID int64, dateStr string) ([]BookedSlot, error) {
 	query := `
 		SELECT start_time, end_time 
 		FROM bookings 
 		WHERE venue_id = ? 
 		AND DATE(start_time) = ? 
-		AND status = 'confirmed'
+		AND (status = 'confirmed' OR (status = 'pending' AND created_at > ?))
 	`
 	
-	rows, err := db.DB.Query(query, venueID, dateStr)
+	rows, err := db.DB.Query(query, venueID, dateStr, HoldCutoff())
 	if err != nil {
 		log.Println("Error querying booked slots:", err)
 		return nil, err
@@ -2,13 +2,24 @@
 package booking
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 	"github.com/JkD004/playarena-backend/notification"
 	"github.com/JkD004/playarena-backend/venue"
 )
 
+// HoldDuration is how long an unpaid 'pending' booking keeps its slot
+// reserved before other players can book it again.
+const HoldDuration = 15 * time.Minute
+
+// HoldCutoff returns the creation time after which pending bookings are still live holds
+func HoldCutoff() time.Time {
+	return time.Now().Add(-HoldDuration)
+}
+
 // CreateNewBooking handles the business logic
 func CreateNewBooking(req *CreateBookingRequest, userID int64) (*Booking, error) {
 	// 1. Get Venue details for pricing
@@ -16,6 +27,9 @@ func CreateNewBooking(req *CreateBookingRequest, userID int64) (*Booking, error)
 	if err != nil {
 		return nil, errors.New("venue not found or not available for booking")
 	}
+	if venueToBook.Lifecycle != venue.LifecycleActive {
+		return nil, errors.New("this venue is not taking bookings right now")
+	}
 
 	// 2. Calculate Duration
 	duration := req.EndTime.Sub(req.StartTime)
@@ -57,6 +71,14 @@ func CreateNewBooking(req *CreateBookingRequest, userID int64) (*Booking, error)
 
 // BlockVenueSlot creates a "blocked" booking (Owner/Admin only)
 func BlockVenueSlot(req *CreateBookingRequest, userID int64) error {
+	if !req.EndTime.After(req.StartTime) {
+		return errors.New("end time must be after start time")
+	}
+	// Blocks only make sense for time that is still to come
+	if !req.StartTime.After(time.Now()) {
+		return errors.New("cannot block a slot that has already started")
+	}
+
 	// 1. Check availability
 	available, err := IsSlotAvailable(req.VenueID, req.StartTime, req.EndTime)
 	if err != nil {
@@ -76,6 +98,7 @@ func BlockVenueSlot(req *CreateBookingRequest, userID int64) error {
 		EndTime:    req.EndTime,
 		TotalPrice: 0,           // <--- FIX: Set to 0 for blocks
 		Status:     "confirmed", // <--- FIX: Confirmed immediately
+		Kind:       KindBlock,
 	}
 
 	err = CreateBooking(newBooking)
@@ -87,6 +110,13 @@ func BlockVenueSlot(req *CreateBookingRequest, userID int64) error {
 	return nil
 }
 
+// ErrNotAwaitingPayment is returned when paying for a booking that isn't pending
+var ErrNotAwaitingPayment = errors.New("booking is not awaiting payment")
+
+// ErrHoldExpired is returned when paying for a pending booking older than
+// HoldDuration: the slot was released and may have been booked since
+var ErrHoldExpired = errors.New("your hold on this slot has expired; please book again")
+
 // ProcessPayment simulates payment processing
 func ProcessPayment(bookingID int64) error {
 	// 1. Fetch the booking details first (to get UserID)
@@ -94,14 +124,28 @@ func ProcessPayment(bookingID int64) error {
 	if err != nil {
 		return errors.New("booking not found")
 	}
+	if booking.Status != "pending" {
+		return ErrNotAwaitingPayment
+	}
+	cutoff := HoldCutoff()
+	if !booking.CreatedAt.After(cutoff) {
+		return ErrHoldExpired
+	}
 
 	// 2. In a real app, verify payment with Stripe/Razorpay here.
 
-	// 3. Update DB status to 'confirmed'
-	err = ConfirmBookingPayment(bookingID)
+	// 3. Update DB status to 'confirmed', as long as the hold is still live
+	confirmed, err := ConfirmBookingPayment(bookingID, cutoff)
 	if err != nil {
 		return err
 	}
+	if !confirmed {
+		// Paid or canceled meanwhile, or the hold ran out while we worked
+		if current, err := FindBookingByID(bookingID); err == nil && current.Status == "pending" {
+			return ErrHoldExpired
+		}
+		return ErrNotAwaitingPayment
+	}
 
 	// 4. Send Notification
 	// We now have booking.UserID from step 1
@@ -111,6 +155,52 @@ func ProcessPayment(bookingID int64) error {
 	return nil
 }
 
+// CheckInWindow is how long before the start time a booking can be checked in
+const CheckInWindow = 30 * time.Minute
+
+var (
+	ErrBookingNotFound  = errors.New("booking not found")
+	ErrNotCheckInable   = errors.New("only confirmed bookings can be checked in")
+	ErrCheckInTooEarly  = errors.New("check-in opens 30 minutes before the booking starts")
+	ErrBookingEnded     = errors.New("this booking has already ended")
+	ErrAlreadyCheckedIn = errors.New("booking is already checked in")
+)
+
+// CheckInBooking marks a player as arrived. The owner, an admin, or staff
+// with the check_in permission (policy.BookingCheckIn) can do this from
+// shortly before the start until the booking ends.
+func CheckInBooking(bookingID, userID int64, userRole string) error {
+	b, err := FindBookingByID(bookingID)
+	if err == sql.ErrNoRows {
+		return ErrBookingNotFound
+	}
+	if err != nil {
+		return errors.New("failed to check in booking")
+	}
+
+	// Blocks have nobody to check in; free player bookings still do
+	if b.Status != "confirmed" || b.Kind == KindBlock {
+		return ErrNotCheckInable
+	}
+	now := time.Now()
+	if now.Before(b.StartTime.Add(-CheckInWindow)) {
+		return ErrCheckInTooEarly
+	}
+	if now.After(b.EndTime) {
+		return ErrBookingEnded
+	}
+
+	ok, err := MarkBookingCheckedIn(bookingID, userID)
+	if err != nil {
+		return errors.New("failed to check in booking")
+	}
+	if !ok {
+		return ErrAlreadyCheckedIn
+	}
+	venue.RecordStaffAction(b.VenueID, userID, userRole, "booking_checked_in", fmt.Sprintf("booking %d", bookingID))
+	return nil
+}
+
 // --- Getters & Helpers ---
 
 // GetBookingsForUser is the service-layer function
@@ -150,7 +240,7 @@ func GetStatisticsForOwner(ownerID int64, venueID int64) (*OwnerStats, error) {
 		TotalRevenue:  revenue,
 		PopularTime:   popTime,
 	}
-	
+
 	return stats, nil
 }
 
@@ -171,7 +261,7 @@ func GetStatisticsForAdmin() (*OwnerStats, error) {
 		TotalRevenue:  revenue,
 		PopularTime:   popTime,
 	}
-	
+
 	return stats, nil
 }
 
@@ -185,7 +275,17 @@ func GetGroupedVenueStats() ([]VenueStats, error) {
 	return GetVenueStatsGrouped()
 }
 
-// Helper to satisfy compiler if "time" is not explicitly used in logic
-func init() {
-	_ = time.Duration(0)
-}
\ No newline at end of file
+// SearchAvailableVenues finds venues that can be booked for the whole window,
+// applying any of the regular venue search filters as well.
+func SearchAvailableVenues(params *venue.VenueSearchParams, start, end time.Time) ([]venue.Venue, string, error) {
+	if start.Before(time.Now()) {
+		return nil, "", errors.New("start must be in the future")
+	}
+
+	params.Available = &venue.AvailabilityWindow{
+		Start:      start,
+		End:        end,
+		HoldCutoff: HoldCutoff(),
+	}
+	return venue.SearchVenues(params)
+}
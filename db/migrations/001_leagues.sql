@@ -0,0 +1,54 @@
+-- db/migrations/001_leagues.sql
+-- Seasonal leagues: seasons, participating teams, fixtures and results.
+
+CREATE TABLE IF NOT EXISTS leagues (
+    id             BIGINT AUTO_INCREMENT PRIMARY KEY,
+    organizer_id   BIGINT NOT NULL,
+    name           VARCHAR(255) NOT NULL,
+    sport_category VARCHAR(100) NOT NULL,
+    points_win     INT NOT NULL DEFAULT 3,
+    points_draw    INT NOT NULL DEFAULT 1,
+    points_loss    INT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organizer_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS league_seasons (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    league_id  BIGINT NOT NULL,
+    name       VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date   DATE NOT NULL,
+    status     VARCHAR(20) NOT NULL DEFAULT 'upcoming', -- upcoming, active, finished
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (league_id) REFERENCES leagues(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS season_teams (
+    season_id BIGINT NOT NULL,
+    team_id   BIGINT NOT NULL,
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (season_id, team_id),
+    FOREIGN KEY (season_id) REFERENCES league_seasons(id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS league_fixtures (
+    id                  BIGINT AUTO_INCREMENT PRIMARY KEY,
+    season_id           BIGINT NOT NULL,
+    home_team_id        BIGINT NOT NULL,
+    away_team_id        BIGINT NOT NULL,
+    booking_id          BIGINT NULL,
+    scheduled_at        DATETIME NOT NULL,
+    status              VARCHAR(20) NOT NULL DEFAULT 'scheduled', -- scheduled, result_pending, completed
+    home_score          INT NULL,
+    away_score          INT NULL,
+    submitted_by        BIGINT NULL,
+    home_confirmed      BOOLEAN NOT NULL DEFAULT FALSE,
+    away_confirmed      BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at          TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (season_id) REFERENCES league_seasons(id) ON DELETE CASCADE,
+    FOREIGN KEY (home_team_id) REFERENCES teams(id),
+    FOREIGN KEY (away_team_id) REFERENCES teams(id),
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL
+);
@@ -0,0 +1,26 @@
+-- db/migrations/002_player_ratings.sql
+-- Per-sport Elo ratings for players, updated from confirmed match results.
+
+CREATE TABLE IF NOT EXISTS player_ratings (
+    user_id      BIGINT NOT NULL,
+    sport        VARCHAR(100) NOT NULL,
+    rating       INT NOT NULL DEFAULT 1200,
+    games_played INT NOT NULL DEFAULT 0,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, sport),
+    INDEX idx_player_ratings_sport_rating (sport, rating),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS player_rating_history (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    sport      VARCHAR(100) NOT NULL,
+    fixture_id BIGINT NULL,
+    old_rating INT NOT NULL,
+    new_rating INT NOT NULL,
+    result     VARCHAR(10) NOT NULL, -- win, draw, loss
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_rating_history_user (user_id, sport, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
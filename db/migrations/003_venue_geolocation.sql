@@ -0,0 +1,7 @@
+-- db/migrations/003_venue_geolocation.sql
+-- Latitude/longitude for venues, indexed for bounding-box prefiltering.
+
+ALTER TABLE venues
+    ADD COLUMN latitude  DECIMAL(9,6) NULL,
+    ADD COLUMN longitude DECIMAL(9,6) NULL,
+    ADD INDEX idx_venues_lat_lng (latitude, longitude);
@@ -0,0 +1,12 @@
+-- db/migrations/004_venue_amenities.sql
+-- Free-text amenity tags per venue, used by the venue search filters.
+
+CREATE TABLE IF NOT EXISTS venue_amenities (
+    venue_id BIGINT NOT NULL,
+    name     VARCHAR(100) NOT NULL,
+    PRIMARY KEY (venue_id, name),
+    INDEX idx_venue_amenities_name (name),
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_venues_status_price ON venues (status, price_per_hour);
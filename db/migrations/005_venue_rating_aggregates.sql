@@ -0,0 +1,24 @@
+-- db/migrations/005_venue_rating_aggregates.sql
+-- Denormalized review aggregates on venues, kept in sync by the venue service.
+
+ALTER TABLE venues
+    ADD COLUMN rating_avg   DECIMAL(3,2) NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN rating_1     INT NOT NULL DEFAULT 0,
+    ADD COLUMN rating_2     INT NOT NULL DEFAULT 0,
+    ADD COLUMN rating_3     INT NOT NULL DEFAULT 0,
+    ADD COLUMN rating_4     INT NOT NULL DEFAULT 0,
+    ADD COLUMN rating_5     INT NOT NULL DEFAULT 0,
+    ADD INDEX idx_venues_rating (status, rating_avg);
+
+-- Backfill from existing reviews
+UPDATE venues v
+JOIN (
+    SELECT venue_id, COUNT(*) AS c, AVG(rating) AS a,
+           SUM(rating = 1) AS r1, SUM(rating = 2) AS r2, SUM(rating = 3) AS r3,
+           SUM(rating = 4) AS r4, SUM(rating = 5) AS r5
+    FROM reviews
+    GROUP BY venue_id
+) s ON s.venue_id = v.id
+SET v.rating_avg = s.a, v.rating_count = s.c,
+    v.rating_1 = s.r1, v.rating_2 = s.r2, v.rating_3 = s.r3, v.rating_4 = s.r4, v.rating_5 = s.r5;
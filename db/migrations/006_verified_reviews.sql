@@ -0,0 +1,39 @@
+-- db/migrations/006_verified_reviews.sql
+-- Reviews are tied to a completed booking (one review per booking)
+-- and can carry a single public reply from the venue owner.
+
+-- CreateReview used to accept any rating, so clamp stray values into 1-5
+-- before the CHECK below (which MySQL 8.0.16+ enforces on existing rows) and
+-- recompute the aggregates from 005 to match.
+UPDATE reviews SET rating = LEAST(GREATEST(rating, 1), 5) WHERE rating NOT BETWEEN 1 AND 5;
+
+UPDATE venues v
+JOIN (
+    SELECT venue_id, COUNT(*) AS c, AVG(rating) AS a,
+           SUM(rating = 1) AS r1, SUM(rating = 2) AS r2, SUM(rating = 3) AS r3,
+           SUM(rating = 4) AS r4, SUM(rating = 5) AS r5
+    FROM reviews
+    GROUP BY venue_id
+) s ON s.venue_id = v.id
+SET v.rating_avg = s.a, v.rating_count = s.c,
+    v.rating_1 = s.r1, v.rating_2 = s.r2, v.rating_3 = s.r3, v.rating_4 = s.r4, v.rating_5 = s.r5;
+
+ALTER TABLE reviews
+    ADD COLUMN booking_id BIGINT NULL,
+    ADD COLUMN updated_at TIMESTAMP NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+    ADD COLUMN reply_text TEXT NULL,
+    ADD COLUMN reply_by   BIGINT NULL,
+    ADD COLUMN replied_at TIMESTAMP NULL,
+    ADD UNIQUE INDEX uq_reviews_booking (booking_id),
+    ADD CONSTRAINT chk_reviews_rating CHECK (rating BETWEEN 1 AND 5),
+    ADD FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL;
+
+-- Owner/staff blocks are stored as bookings; 'kind' tells them apart so a
+-- block can never pass as a completed stay. Older rows carry no marker, so
+-- the backfill deliberately guesses: blocks were the only free, confirmed
+-- rows BlockVenueSlot created. This heuristic is used here once only; from
+-- now on code tells blocks apart by 'kind' alone, never by price or status.
+ALTER TABLE bookings
+    ADD COLUMN kind VARCHAR(10) NOT NULL DEFAULT 'booking'; -- booking, block
+
+UPDATE bookings SET kind = 'block' WHERE status = 'confirmed' AND total_price = 0;
@@ -0,0 +1,23 @@
+-- db/migrations/007_review_moderation.sql
+-- Review moderation state and user abuse reports.
+
+ALTER TABLE reviews
+    ADD COLUMN moderation_status VARCHAR(20) NOT NULL DEFAULT 'published', -- published, held, hidden
+    ADD COLUMN moderation_note   VARCHAR(255) NULL,
+    ADD INDEX idx_reviews_venue_status (venue_id, moderation_status),
+    ADD INDEX idx_reviews_user_created (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS review_reports (
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    review_id   BIGINT NOT NULL,
+    reporter_id BIGINT NOT NULL,
+    reason      VARCHAR(500) NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'open', -- open, resolved
+    resolved_by BIGINT NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_review_reports_reporter (review_id, reporter_id),
+    INDEX idx_review_reports_status (status),
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+-- db/migrations/008_review_photos.sql
+-- Photos attached to reviews. public_id is the storage key used for deletion.
+
+CREATE TABLE IF NOT EXISTS review_photos (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    review_id  BIGINT NOT NULL,
+    image_url  VARCHAR(500) NOT NULL,
+    public_id  VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_review_photos_review (review_id),
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+-- db/migrations/009_storage_keys.sql
+-- Media now goes through the storage package. Every stored file keeps the
+-- backend key it was saved under so it can be deleted later. Rows uploaded
+-- before this migration have no key and are simply left in place on delete.
+
+ALTER TABLE venue_photos
+    ADD COLUMN storage_key VARCHAR(255) NULL AFTER image_url;
+
+ALTER TABLE review_photos
+    CHANGE COLUMN public_id storage_key VARCHAR(255) NOT NULL;
+
+ALTER TABLE users
+    ADD COLUMN avatar_key VARCHAR(255) NULL AFTER avatar_url;
@@ -0,0 +1,12 @@
+-- db/migrations/010_photo_variants.sql
+-- Venue photos are re-encoded into standard sizes on upload.
+-- image_url / storage_key hold the "full" variant. Older rows have no
+-- card/thumbnail and fall back to image_url when read.
+
+ALTER TABLE venue_photos
+    ADD COLUMN card_url      VARCHAR(500) NULL AFTER storage_key,
+    ADD COLUMN card_key      VARCHAR(255) NULL AFTER card_url,
+    ADD COLUMN thumbnail_url VARCHAR(500) NULL AFTER card_key,
+    ADD COLUMN thumbnail_key VARCHAR(255) NULL AFTER thumbnail_url,
+    ADD COLUMN width         INT NULL AFTER thumbnail_key,
+    ADD COLUMN height        INT NULL AFTER width;
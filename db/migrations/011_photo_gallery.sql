@@ -0,0 +1,34 @@
+-- db/migrations/011_photo_gallery.sql
+-- Ordered venue galleries with captions and a cover photo.
+-- A venue with photos always has a cover; it defaults to the first photo.
+
+ALTER TABLE venue_photos
+    ADD COLUMN position INT NOT NULL DEFAULT 0 AFTER venue_id,
+    ADD COLUMN caption  VARCHAR(200) NULL AFTER position,
+    ADD INDEX idx_venue_photos_position (venue_id, position);
+
+-- Keep the current (upload) order for existing photos
+UPDATE venue_photos SET position = id;
+
+ALTER TABLE venues
+    ADD COLUMN cover_photo_id BIGINT NULL,
+    ADD CONSTRAINT fk_venues_cover_photo
+        FOREIGN KEY (cover_photo_id) REFERENCES venue_photos(id) ON DELETE SET NULL;
+
+UPDATE venues v
+SET cover_photo_id = (
+    SELECT p.id FROM venue_photos p WHERE p.venue_id = v.id ORDER BY p.position, p.id LIMIT 1
+);
+
+-- Stored files waiting to be deleted. A row is written before the delete is
+-- attempted and removed once it succeeds; failures are retried in the
+-- background with a growing delay.
+CREATE TABLE IF NOT EXISTS pending_deletes (
+    id              BIGINT AUTO_INCREMENT PRIMARY KEY,
+    storage_key     VARCHAR(255) NOT NULL,
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      VARCHAR(500) NULL,
+    next_attempt_at DATETIME NOT NULL,
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_pending_deletes_due (next_attempt_at)
+);
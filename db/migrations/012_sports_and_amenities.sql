@@ -0,0 +1,110 @@
+-- db/migrations/012_sports_and_amenities.sql
+-- Managed sports and amenities reference tables. Venues link to one or more
+-- sports (the primary one is mirrored into venues.sport_category so existing
+-- queries and stats keep working) and to any number of amenities.
+-- Existing free-text values are migrated onto the new tables.
+
+CREATE TABLE IF NOT EXISTS sports (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    slug       VARCHAR(50)  NOT NULL UNIQUE,
+    name       VARCHAR(100) NOT NULL,
+    active     BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS sport_aliases (
+    alias    VARCHAR(50) PRIMARY KEY,
+    sport_id BIGINT NOT NULL,
+    FOREIGN KEY (sport_id) REFERENCES sports(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS amenities (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    slug       VARCHAR(50)  NOT NULL UNIQUE,
+    name       VARCHAR(100) NOT NULL,
+    active     BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS venue_sports (
+    venue_id   BIGINT  NOT NULL,
+    sport_id   BIGINT  NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (venue_id, sport_id),
+    INDEX idx_venue_sports_sport (sport_id),
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE,
+    FOREIGN KEY (sport_id) REFERENCES sports(id)
+);
+
+-- ---- Seed data ----
+INSERT IGNORE INTO sports (slug, name) VALUES
+    ('football', 'Football'),
+    ('cricket', 'Cricket'),
+    ('badminton', 'Badminton'),
+    ('tennis', 'Tennis'),
+    ('table_tennis', 'Table Tennis'),
+    ('basketball', 'Basketball'),
+    ('volleyball', 'Volleyball'),
+    ('swimming', 'Swimming');
+
+INSERT IGNORE INTO sport_aliases (alias, sport_id)
+SELECT 'soccer', id FROM sports WHERE slug = 'football'
+UNION ALL SELECT 'futsal', id FROM sports WHERE slug = 'football'
+UNION ALL SELECT 'ping_pong', id FROM sports WHERE slug = 'table_tennis';
+
+INSERT IGNORE INTO amenities (slug, name) VALUES
+    ('parking', 'Parking'),
+    ('floodlights', 'Floodlights'),
+    ('changing_rooms', 'Changing Rooms'),
+    ('showers', 'Showers'),
+    ('equipment_rental', 'Equipment Rental');
+
+-- ---- Migrate venues.sport_category ----
+-- Free text is slugged exactly like taxonomy.Slugify: lower case, every run
+-- of other characters becomes one '_', none at either end ("Table-Tennis"
+-- -> "table_tennis"), so migrated values match what the API resolves.
+-- Categories that match neither a slug nor an alias become sports of their own
+INSERT IGNORE INTO sports (slug, name)
+SELECT DISTINCT TRIM(BOTH '_' FROM REGEXP_REPLACE(LOWER(TRIM(sport_category)), '[^[:alnum:]]+', '_')), TRIM(sport_category)
+FROM venues
+WHERE sport_category IS NOT NULL AND TRIM(sport_category) <> ''
+  AND TRIM(BOTH '_' FROM REGEXP_REPLACE(LOWER(TRIM(sport_category)), '[^[:alnum:]]+', '_')) NOT IN (SELECT alias FROM sport_aliases);
+
+INSERT IGNORE INTO venue_sports (venue_id, sport_id, is_primary)
+SELECT v.id, s.id, TRUE
+FROM venues v
+JOIN sports s ON s.id = COALESCE(
+    (SELECT a.sport_id FROM sport_aliases a WHERE a.alias = TRIM(BOTH '_' FROM REGEXP_REPLACE(LOWER(TRIM(v.sport_category)), '[^[:alnum:]]+', '_'))),
+    (SELECT s2.id FROM sports s2 WHERE s2.slug = TRIM(BOTH '_' FROM REGEXP_REPLACE(LOWER(TRIM(v.sport_category)), '[^[:alnum:]]+', '_')))
+);
+
+-- "football", "Football" and "Soccer" all become "Football"
+UPDATE venues v
+JOIN venue_sports vs ON vs.venue_id = v.id AND vs.is_primary = TRUE
+JOIN sports s ON s.id = vs.sport_id
+SET v.sport_category = s.name;
+
+-- ---- Migrate free-text venue_amenities onto amenity IDs ----
+INSERT IGNORE INTO amenities (slug, name)
+SELECT DISTINCT TRIM(BOTH '_' FROM REGEXP_REPLACE(LOWER(TRIM(name)), '[^[:alnum:]]+', '_')), TRIM(name)
+FROM venue_amenities;
+
+ALTER TABLE venue_amenities ADD COLUMN amenity_id BIGINT NULL;
+
+UPDATE venue_amenities va
+JOIN amenities a ON a.slug = TRIM(BOTH '_' FROM REGEXP_REPLACE(LOWER(TRIM(va.name)), '[^[:alnum:]]+', '_'))
+SET va.amenity_id = a.id;
+
+-- Drop rows that now point at the same amenity ("changing rooms" / "changing_rooms")
+DELETE va1 FROM venue_amenities va1
+JOIN venue_amenities va2
+  ON va1.venue_id = va2.venue_id AND va1.amenity_id = va2.amenity_id AND va1.name > va2.name;
+
+ALTER TABLE venue_amenities
+    DROP PRIMARY KEY,
+    DROP INDEX idx_venue_amenities_name,
+    DROP COLUMN name,
+    MODIFY amenity_id BIGINT NOT NULL,
+    ADD PRIMARY KEY (venue_id, amenity_id),
+    ADD INDEX idx_venue_amenities_amenity (amenity_id),
+    ADD CONSTRAINT fk_venue_amenities_amenity FOREIGN KEY (amenity_id) REFERENCES amenities(id);
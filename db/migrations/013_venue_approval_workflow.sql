@@ -0,0 +1,21 @@
+-- db/migrations/013_venue_approval_workflow.sql
+-- Venue approval workflow: pending -> approved | rejected | changes_requested,
+-- owner resubmission back to pending, and a per-venue decision history.
+
+ALTER TABLE venues
+    MODIFY COLUMN status VARCHAR(30) NOT NULL DEFAULT 'pending',
+    ADD COLUMN status_note TEXT NULL AFTER status;
+
+CREATE TABLE IF NOT EXISTS venue_status_history (
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    venue_id    BIGINT      NOT NULL,
+    actor_id    BIGINT      NOT NULL,
+    actor_role  VARCHAR(20) NOT NULL,
+    from_status VARCHAR(30) NULL, -- NULL for the initial submission
+    to_status   VARCHAR(30) NOT NULL,
+    note        TEXT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_venue_status_history_venue (venue_id, created_at),
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id)
+);
@@ -0,0 +1,21 @@
+-- db/migrations/014_venue_revisions.sql
+-- Owner edits to sensitive fields (name, address/location, sports, price) of
+-- an approved venue are staged here until an admin approves them.
+-- A venue has at most one pending revision; a new edit replaces it.
+
+CREATE TABLE IF NOT EXISTS venue_revisions (
+    id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+    venue_id     BIGINT      NOT NULL,
+    submitted_by BIGINT      NOT NULL,
+    changes      JSON        NOT NULL, -- proposed values, see venue.VenueRevisionFields
+    status       VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, rejected
+    reviewed_by  BIGINT      NULL,
+    review_note  TEXT        NULL,
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at  TIMESTAMP   NULL,
+    INDEX idx_venue_revisions_status (status, created_at),
+    INDEX idx_venue_revisions_venue (venue_id, status),
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE,
+    FOREIGN KEY (submitted_by) REFERENCES users(id),
+    FOREIGN KEY (reviewed_by) REFERENCES users(id)
+);
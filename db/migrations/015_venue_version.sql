@@ -0,0 +1,6 @@
+-- db/migrations/015_venue_version.sql
+-- Optimistic locking for venue edits. Every update bumps the version and is
+-- only applied if the client's version (sent as If-Match / "version") is current.
+
+ALTER TABLE venues
+    ADD COLUMN version INT NOT NULL DEFAULT 1 AFTER status_note;
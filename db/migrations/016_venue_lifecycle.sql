@@ -0,0 +1,17 @@
+-- db/migrations/016_venue_lifecycle.sql
+-- Lifecycle is separate from approval status: an approved venue can be taken
+-- offline ('inactive') or removed ('archived') without losing its bookings,
+-- reviews and approval history. Only active venues appear in search and can
+-- be booked. Archiving cancels or waits on future bookings.
+
+ALTER TABLE venues
+    ADD COLUMN lifecycle VARCHAR(20) NOT NULL DEFAULT 'active' AFTER version, -- active, inactive, archived
+    ADD COLUMN lifecycle_changed_at TIMESTAMP NULL AFTER lifecycle,
+    ADD INDEX idx_venues_lifecycle (status, lifecycle);
+
+-- Bookings canceled because their venue was archived. Paid bookings move to
+-- 'refund_due' (nothing pays the player back automatically; support settles
+-- them and marks them refunded); unpaid holds and owner blocks move to
+-- 'canceled'.
+ALTER TABLE bookings
+    ADD COLUMN canceled_reason VARCHAR(255) NULL AFTER status;
@@ -0,0 +1,38 @@
+-- db/migrations/017_venue_staff.sql
+-- Owners invite staff to a venue with scoped permissions instead of sharing
+-- their password. Invites stay 'pending' until the staff member accepts.
+-- Permissions: view_bookings, block_slots, check_in, edit_listing, view_revenue
+
+CREATE TABLE IF NOT EXISTS venue_staff (
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    venue_id    BIGINT      NOT NULL,
+    user_id     BIGINT      NOT NULL,
+    permissions JSON        NOT NULL, -- array of permission names
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, active
+    invited_by  BIGINT      NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP   NULL,
+    UNIQUE KEY uq_venue_staff (venue_id, user_id),
+    INDEX idx_venue_staff_user (user_id),
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id)
+);
+
+-- Audit trail of what staff did at a venue, and of changes to their access
+CREATE TABLE IF NOT EXISTS venue_staff_actions (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    venue_id   BIGINT      NOT NULL,
+    user_id    BIGINT      NOT NULL, -- who acted
+    action     VARCHAR(50) NOT NULL, -- e.g. slot_blocked, booking_checked_in, staff_invited
+    details    TEXT        NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_venue_staff_actions_venue (venue_id, created_at),
+    FOREIGN KEY (venue_id) REFERENCES venues(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- Check-in at the venue (by the owner or staff with the check_in permission)
+ALTER TABLE bookings
+    ADD COLUMN checked_in_at TIMESTAMP NULL,
+    ADD COLUMN checked_in_by BIGINT NULL;
@@ -0,0 +1,8 @@
+-- db/migrations/018_account_state.sql
+-- Account state checked on every authenticated request. Tokens carry the
+-- token_version they were issued with; bumping it revokes them all.
+
+ALTER TABLE users
+    ADD COLUMN status            VARCHAR(20) NOT NULL DEFAULT 'active' AFTER role, -- active, suspended
+    ADD COLUMN token_version     INT         NOT NULL DEFAULT 1 AFTER status,
+    ADD COLUMN status_changed_at TIMESTAMP   NULL AFTER token_version;
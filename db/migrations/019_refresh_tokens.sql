@@ -0,0 +1,19 @@
+-- db/migrations/019_refresh_tokens.sql
+-- Rotating refresh tokens. Only a SHA-256 hash of each token is stored. Every
+-- refresh marks the presented token used and issues the next one in the same
+-- family; presenting a used token again revokes the whole family.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id    BIGINT      NOT NULL,
+    family_id  CHAR(32)    NOT NULL, -- one family per login
+    token_hash CHAR(64)    NOT NULL,
+    expires_at TIMESTAMP   NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    used_at    TIMESTAMP   NULL, -- set when rotated
+    revoked_at TIMESTAMP   NULL, -- set on logout or detected reuse
+    UNIQUE KEY uq_refresh_token_hash (token_hash),
+    INDEX idx_refresh_tokens_family (family_id),
+    INDEX idx_refresh_tokens_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+-- db/migrations/020_jwt_keys.sql
+-- Signing keys for managed JWT keys (JWT_ALG=RS256/EdDSA or JWT_KEY_ROTATION
+-- set). A new key is published before it signs anything (active_at), and an
+-- old key keeps verifying until the tokens it signed have expired (retire_at).
+-- private_key holds signing secrets: set JWT_KEY_ENCRYPTION_KEY to encrypt
+-- them, and otherwise protect this table (and its backups) like a secret.
+
+CREATE TABLE IF NOT EXISTS jwt_keys (
+    kid         VARCHAR(64) PRIMARY KEY,
+    alg         VARCHAR(10) NOT NULL, -- HS256, RS256, EdDSA
+    private_key TEXT        NOT NULL, -- PKCS#8 PEM, or the base64 secret for HS256; "enc:v1:" + AES-GCM when JWT_KEY_ENCRYPTION_KEY is set
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    active_at   TIMESTAMP   NOT NULL,
+    retire_at   TIMESTAMP   NULL,
+    verify_only BOOLEAN     NOT NULL DEFAULT FALSE, -- JWT_SECRET/JWT_PREVIOUS_SECRETS seeded for HS256; never signs
+    INDEX idx_jwt_keys_alg (alg, active_at)
+);
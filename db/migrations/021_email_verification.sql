@@ -0,0 +1,20 @@
+-- db/migrations/021_email_verification.sql
+-- New accounts confirm their email before booking or listing a venue.
+-- Accounts that existed before this migration count as verified.
+
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMP NULL AFTER email;
+
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- Only a SHA-256 hash of each emailed token is stored
+CREATE TABLE IF NOT EXISTS email_verifications (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id    BIGINT    NOT NULL,
+    token_hash CHAR(64)  NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_email_verification_token (token_hash),
+    INDEX idx_email_verifications_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
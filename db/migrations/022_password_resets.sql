@@ -0,0 +1,15 @@
+-- db/migrations/022_password_resets.sql
+-- Single-use password reset tokens. Only a SHA-256 hash of each emailed token
+-- is stored; used_at is set when the token is redeemed.
+
+CREATE TABLE IF NOT EXISTS password_resets (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id    BIGINT    NOT NULL,
+    token_hash CHAR(64)  NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    used_at    TIMESTAMP NULL,
+    UNIQUE KEY uq_password_reset_token (token_hash),
+    INDEX idx_password_resets_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,68 @@
+-- db/migrations/023_phone_otp.sql
+-- Phone verification and passwordless login with one-time codes sent by SMS.
+-- Codes are stored as bcrypt hashes; each allows a few attempts and expires
+-- after a few minutes. Rows double as the log for per-phone and per-IP rate
+-- limits.
+
+ALTER TABLE users
+    ADD COLUMN phone_verified_at TIMESTAMP NULL AFTER phone;
+
+-- A number identifies an account for login, so it may belong to one user
+-- only. Store numbers the way normalizePhone does, with NULL for none. Where
+-- several accounts share a number it stays on the oldest; the others are
+-- copied to phone_conflicts first, so support can contact those users and
+-- restore a number once the conflict is settled.
+UPDATE users SET phone = REGEXP_REPLACE(TRIM(phone), '[ .()-]', '') WHERE phone IS NOT NULL;
+UPDATE users SET phone = NULL WHERE phone = '';
+
+CREATE TABLE IF NOT EXISTS phone_conflicts (
+    user_id      BIGINT      NOT NULL PRIMARY KEY, -- account whose number was cleared
+    phone        VARCHAR(20) NOT NULL,
+    kept_user_id BIGINT      NOT NULL,             -- account that kept the number
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO phone_conflicts (user_id, phone, kept_user_id)
+SELECT d.id, d.phone, d.kept
+FROM (
+    SELECT id, phone,
+           ROW_NUMBER() OVER (PARTITION BY phone ORDER BY id) AS n,
+           MIN(id) OVER (PARTITION BY phone) AS kept
+    FROM users
+    WHERE phone IS NOT NULL
+) d
+WHERE d.n > 1;
+
+UPDATE users u
+JOIN phone_conflicts c ON c.user_id = u.id
+SET u.phone = NULL;
+ALTER TABLE users
+    ADD UNIQUE INDEX uq_users_phone (phone);
+
+CREATE TABLE IF NOT EXISTS phone_otps (
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    phone       VARCHAR(20) NOT NULL,
+    purpose     VARCHAR(10) NOT NULL, -- verify, login
+    user_id     BIGINT      NULL,     -- NULL when no account has the number
+    code_hash   VARCHAR(60) NOT NULL,
+    attempts    INT         NOT NULL DEFAULT 0,
+    request_ip  VARCHAR(45) NOT NULL,
+    expires_at  TIMESTAMP   NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    consumed_at TIMESTAMP   NULL,
+    INDEX idx_phone_otps_phone (phone, purpose, created_at),
+    INDEX idx_phone_otps_ip (request_ip, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Wrong codes, by number and by the address that guessed them. Each code
+-- allows only a few guesses, but a number can be sent many codes, so these
+-- limit guessing across codes and numbers.
+CREATE TABLE IF NOT EXISTS otp_failures (
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    phone      VARCHAR(20) NOT NULL,
+    ip         VARCHAR(45) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_otp_failures_phone (phone, created_at),
+    INDEX idx_otp_failures_ip (ip, created_at)
+);
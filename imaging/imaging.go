@@ -0,0 +1,144 @@
+// imaging/imaging.go
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder
+	"io"
+	"net/http"
+)
+
+// Limits applied to every decoded upload
+const (
+	MaxPixels    = 40_000_000 // guards against decompression bombs
+	MinDimension = 64
+	JPEGQuality  = 85
+)
+
+// ContentType of every re-encoded variant
+const ContentType = "image/jpeg"
+
+// allowedTypes are the sniffed types we can decode with the standard library
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
+// ValidationError means the upload itself was rejected (bad type, too big, ...)
+// rather than failing during processing. Handlers map it to a 400.
+type ValidationError struct {
+	msg string
+}
+
+func (e *ValidationError) Error() string {
+	return e.msg
+}
+
+func invalid(format string, args ...interface{}) error {
+	return &ValidationError{msg: fmt.Sprintf(format, args...)}
+}
+
+// Variant describes one standard size produced for an upload
+type Variant struct {
+	Name      string
+	MaxWidth  int
+	MaxHeight int
+	Crop      bool // fill the exact box (center crop) instead of fitting inside it
+}
+
+// Standard sizes for venue photos
+var (
+	Thumbnail = Variant{Name: "thumbnail", MaxWidth: 240, MaxHeight: 240, Crop: true}
+	Card      = Variant{Name: "card", MaxWidth: 640, MaxHeight: 480}
+	Full      = Variant{Name: "full", MaxWidth: 1920, MaxHeight: 1920}
+	Avatar    = Variant{Name: "avatar", MaxWidth: 512, MaxHeight: 512, Crop: true}
+)
+
+// Encoded is a re-encoded variant ready to be stored
+type Encoded struct {
+	Variant Variant
+	Data    []byte
+	Width   int
+	Height  int
+}
+
+// Decode reads at most maxBytes, checks the real (sniffed) type and the
+// dimensions, decodes the image and applies any EXIF orientation.
+// Metadata (EXIF, GPS, ...) is never carried over: variants are re-encoded
+// from pixels only.
+func Decode(r io.Reader, maxBytes int64) (image.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading upload: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, invalid("image must be %d MB or smaller", maxBytes>>20)
+	}
+
+	contentType := http.DetectContentType(data)
+	if !allowedTypes[contentType] {
+		return nil, invalid("only JPEG and PNG images are allowed")
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, invalid("image could not be read")
+	}
+	if cfg.Width < MinDimension || cfg.Height < MinDimension {
+		return nil, invalid("image must be at least %dx%d pixels", MinDimension, MinDimension)
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, invalid("image has too many pixels")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, invalid("image could not be read")
+	}
+
+	if contentType == "image/jpeg" {
+		img = applyOrientation(toRGBA(img), exifOrientation(data))
+	}
+	return img, nil
+}
+
+// Render produces each variant as a JPEG
+func Render(img image.Image, variants ...Variant) ([]Encoded, error) {
+	src := toRGBA(img)
+
+	out := make([]Encoded, 0, len(variants))
+	for _, v := range variants {
+		var resized *image.RGBA
+		if v.Crop {
+			resized = fill(src, v.MaxWidth, v.MaxHeight)
+		} else {
+			resized = fit(src, v.MaxWidth, v.MaxHeight)
+		}
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+			return nil, fmt.Errorf("encoding %s variant: %w", v.Name, err)
+		}
+		b := resized.Bounds()
+		out = append(out, Encoded{Variant: v, Data: buf.Bytes(), Width: b.Dx(), Height: b.Dy()})
+	}
+	return out, nil
+}
+
+// toRGBA copies the image onto an opaque white canvas. JPEG has no alpha,
+// so transparent PNG areas become white instead of black.
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) && rgba.Opaque() {
+		return rgba
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
@@ -0,0 +1,80 @@
+// imaging/imaging_resize.go
+package imaging
+
+import (
+	"image"
+)
+
+// fit scales the image down to fit inside maxW x maxH, keeping the aspect
+// ratio. Images are never scaled up.
+func fit(src *image.RGBA, maxW, maxH int) *image.RGBA {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	if w <= maxW && h <= maxH {
+		return src
+	}
+
+	dw, dh := maxW, h*maxW/w
+	if dh > maxH {
+		dw, dh = w*maxH/h, maxH
+	}
+	return resize(src, max(dw, 1), max(dh, 1))
+}
+
+// fill center-crops the image to the aspect ratio of w x h and scales it to
+// exactly that size (or smaller, keeping the ratio, if the source is small).
+func fill(src *image.RGBA, w, h int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+
+	cw, ch := sw, sw*h/w
+	if ch > sh {
+		cw, ch = sh*w/h, sh
+	}
+	x0, y0 := (sw-cw)/2, (sh-ch)/2
+	cropped := src.SubImage(image.Rect(x0, y0, x0+cw, y0+ch)).(*image.RGBA)
+
+	if cw <= w {
+		return resize(cropped, cw, ch)
+	}
+	return resize(cropped, w, h)
+}
+
+// resize scales with a box filter: every destination pixel is the average
+// of the source pixels it covers. Good quality for downscaling and cheap.
+func resize(src *image.RGBA, dw, dh int) *image.RGBA {
+	b := src.Rect
+	sw, sh := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for dy := 0; dy < dh; dy++ {
+		sy0, sy1 := dy*sh/dh, (dy+1)*sh/dh
+		if sy1 == sy0 {
+			sy1 = sy0 + 1
+		}
+		for dx := 0; dx < dw; dx++ {
+			sx0, sx1 := dx*sw/dw, (dx+1)*sw/dw
+			if sx1 == sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, bl, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				i := src.PixOffset(b.Min.X+sx0, b.Min.Y+sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint32(src.Pix[i])
+					g += uint32(src.Pix[i+1])
+					bl += uint32(src.Pix[i+2])
+					a += uint32(src.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+
+			o := dst.PixOffset(dx, dy)
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(bl / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,292 @@
+// jwtkeys/jwtkeys.go
+package jwtkeys
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+var (
+	// ErrNotConfigured is returned when no signing key is available
+	ErrNotConfigured = errors.New("jwt keys are not configured")
+	// ErrUnknownKey is returned for tokens whose kid is not in the keyset
+	ErrUnknownKey = errors.New("unknown signing key")
+)
+
+// Key is one signing key. Tokens are signed with the newest active key and
+// verified with whichever key their kid names, until that key retires.
+type Key struct {
+	ID       string
+	Alg      string
+	ActiveAt time.Time // the key signs tokens from this time on
+	RetireAt time.Time // zero: verifies forever
+
+	// VerifyOnly keys never sign. Managed HS256 keysets seed JWT_SECRET and
+	// JWT_PREVIOUS_SECRETS this way so tokens from before rotation still work.
+	VerifyOnly bool
+
+	signKey   interface{} // []byte, *rsa.PrivateKey or ed25519.PrivateKey
+	verifyKey interface{} // []byte, *rsa.PublicKey or ed25519.PublicKey
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Alg)
+}
+
+// Keyset holds the keys of one algorithm
+type Keyset struct {
+	alg string
+
+	mu   sync.RWMutex
+	keys []Key // newest ActiveAt first
+}
+
+func newKeyset(alg string, keys []Key) *Keyset {
+	ks := &Keyset{alg: alg}
+	ks.setKeys(keys)
+	return ks
+}
+
+func (ks *Keyset) setKeys(keys []Key) {
+	sorted := append([]Key(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].ActiveAt.Equal(sorted[j].ActiveAt) {
+			return sorted[i].ActiveAt.After(sorted[j].ActiveAt)
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+	ks.mu.Lock()
+	ks.keys = sorted
+	ks.mu.Unlock()
+}
+
+// Alg is the algorithm every key in the set uses
+func (ks *Keyset) Alg() string {
+	return ks.alg
+}
+
+// Keys returns a copy of the keys, newest first
+func (ks *Keyset) Keys() []Key {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return append([]Key(nil), ks.keys...)
+}
+
+// signingKey is the newest key that is already active
+func (ks *Keyset) signingKey(now time.Time) (*Key, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for i := range ks.keys {
+		k := ks.keys[i]
+		if !k.VerifyOnly && !k.ActiveAt.After(now) && (k.RetireAt.IsZero() || now.Before(k.RetireAt)) {
+			return &k, nil
+		}
+	}
+	return nil, ErrNotConfigured
+}
+
+func (ks *Keyset) verificationKey(kid string, now time.Time) (*Key, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for i := range ks.keys {
+		k := ks.keys[i]
+		if k.ID == kid {
+			if !k.RetireAt.IsZero() && !now.Before(k.RetireAt) {
+				return nil, ErrUnknownKey
+			}
+			return &k, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// Sign signs the claims with the current key and names it in the kid header
+func (ks *Keyset) Sign(claims jwt.Claims) (string, error) {
+	k, err := ks.signingKey(time.Now())
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(k.method(), claims)
+	token.Header["kid"] = k.ID
+	return token.SignedString(k.signKey)
+}
+
+// Parse verifies a token and fills claims. Only the keyset's algorithm is
+// accepted, and the token must name a known key.
+func (ks *Keyset) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrUnknownKey
+		}
+		k, err := ks.verificationKey(kid, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != k.Alg {
+			return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+		}
+		return k.verifyKey, nil
+	}, jwt.WithValidMethods([]string{ks.alg}), jwt.WithExpirationRequired())
+}
+
+var (
+	defaultMu  sync.Mutex
+	defaultSet *Keyset
+)
+
+// Use makes ks the keyset used by Sign and Parse
+func Use(ks *Keyset) {
+	defaultMu.Lock()
+	defaultSet = ks
+	defaultMu.Unlock()
+}
+
+// Default returns the keyset set with Use. Without one, static keys are read
+// from the environment on first use.
+func Default() (*Keyset, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultSet != nil {
+		return defaultSet, nil
+	}
+	ks, err := newStaticFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	defaultSet = ks
+	return ks, nil
+}
+
+// Sign signs claims with the default keyset
+func Sign(claims jwt.Claims) (string, error) {
+	ks, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return ks.Sign(claims)
+}
+
+// Parse verifies a token with the default keyset
+func Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	ks, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return ks.Parse(tokenString, claims)
+}
+
+// NewFromEnv builds the keyset selected by JWT_ALG:
+//
+//	HS256 (default) - JWT_SECRET signs; JWT_PREVIOUS_SECRETS (comma separated)
+//	                  still verify, so the secret can be rotated without
+//	                  logging anyone out
+//	RS256 / EdDSA   - key pairs are generated and kept in the jwt_keys table;
+//	                  public keys are published as a JWKS
+//
+// Setting JWT_KEY_ROTATION (e.g. "720h") rotates keys on that schedule; this
+// also moves HS256 keys into the jwt_keys table. JWT_SECRET and
+// JWT_PREVIOUS_SECRETS are then stored as verify-only keys that retire after
+// JWT_KEY_RETAIN, so switching rotation on logs no one out. Managed keys need
+// the database and are reloaded in the background. They are encrypted in
+// jwt_keys with JWT_KEY_ENCRYPTION_KEY when it is set (see configureSealing).
+func NewFromEnv() (*Keyset, error) {
+	alg := strings.TrimSpace(os.Getenv("JWT_ALG"))
+	if alg == "" {
+		alg = HS256
+	}
+	switch alg {
+	case HS256, RS256, EdDSA:
+	default:
+		return nil, fmt.Errorf("unknown JWT_ALG %q", alg)
+	}
+
+	rotation, err := envDuration("JWT_KEY_ROTATION", 0)
+	if err != nil {
+		return nil, err
+	}
+	if alg == HS256 && rotation == 0 {
+		return newStaticFromEnv()
+	}
+
+	retain, err := envDuration("JWT_KEY_RETAIN", defaultRetain)
+	if err != nil {
+		return nil, err
+	}
+	return newManaged(alg, rotation, retain)
+}
+
+// newStaticFromEnv builds an HS256 keyset from JWT_SECRET and
+// JWT_PREVIOUS_SECRETS
+func newStaticFromEnv() (*Keyset, error) {
+	secrets := envSecrets()
+	if len(secrets) == 0 {
+		return nil, ErrNotConfigured
+	}
+
+	// The current secret is newest; previous ones only verify
+	now := time.Now()
+	keys := make([]Key, len(secrets))
+	for i, secret := range secrets {
+		keys[i] = secretKey(secret, now.Add(-time.Duration(i)*time.Second))
+	}
+	return newKeyset(HS256, keys), nil
+}
+
+// envSecrets returns JWT_SECRET followed by JWT_PREVIOUS_SECRETS, or nothing
+// when JWT_SECRET is unset
+func envSecrets() []string {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil
+	}
+	secrets := []string{secret}
+	for _, prev := range strings.Split(os.Getenv("JWT_PREVIOUS_SECRETS"), ",") {
+		prev = strings.TrimSpace(prev)
+		if prev == "" || prev == secret {
+			continue
+		}
+		secrets = append(secrets, prev)
+	}
+	return secrets
+}
+
+// secretKey derives the kid from the secret so rotating needs no extra config
+func secretKey(secret string, activeAt time.Time) Key {
+	sum := sha256.Sum256([]byte(secret))
+	return Key{
+		ID:        "hs-" + hex.EncodeToString(sum[:4]),
+		Alg:       HS256,
+		ActiveAt:  activeAt,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+func envDuration(name string, def time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	return d, nil
+}
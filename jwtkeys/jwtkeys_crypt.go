@@ -0,0 +1,101 @@
+// jwtkeys/jwtkeys_crypt.go
+package jwtkeys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// sealedPrefix marks a jwt_keys.private_key value encrypted with
+// JWT_KEY_ENCRYPTION_KEY. Values without it are stored in the clear (keys
+// created before a key was configured) and still load.
+const sealedPrefix = "enc:v1:"
+
+var (
+	sealMu   sync.RWMutex
+	sealAEAD cipher.AEAD // nil: keys are stored in the clear
+)
+
+// configureSealing reads JWT_KEY_ENCRYPTION_KEY, a base64 encoded 32-byte
+// key, and encrypts managed keys with AES-256-GCM from then on. Without it
+// keys are stored in the clear, and the jwt_keys table must be protected like
+// any other secret.
+func configureSealing() error {
+	raw := strings.TrimSpace(os.Getenv("JWT_KEY_ENCRYPTION_KEY"))
+	if raw == "" {
+		log.Println("⚠️  JWT_KEY_ENCRYPTION_KEY is not set, JWT signing keys are stored unencrypted in jwt_keys")
+		setSealing(nil)
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(key) != 32 {
+		return errors.New("JWT_KEY_ENCRYPTION_KEY must be 32 bytes, base64 encoded")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	setSealing(aead)
+	return nil
+}
+
+func setSealing(aead cipher.AEAD) {
+	sealMu.Lock()
+	sealAEAD = aead
+	sealMu.Unlock()
+}
+
+func sealing() cipher.AEAD {
+	sealMu.RLock()
+	defer sealMu.RUnlock()
+	return sealAEAD
+}
+
+// sealKey encrypts a key's stored form. The kid is authenticated with it, so
+// a value copied onto another row does not decrypt.
+func sealKey(kid, encoded string) (string, error) {
+	aead := sealing()
+	if aead == nil {
+		return encoded, nil
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(encoded), []byte(kid))
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openKey reverses sealKey; values stored in the clear pass through
+func openKey(kid, stored string) (string, error) {
+	if !strings.HasPrefix(stored, sealedPrefix) {
+		return stored, nil
+	}
+	aead := sealing()
+	if aead == nil {
+		return "", errors.New("key is encrypted but JWT_KEY_ENCRYPTION_KEY is not set")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, sealedPrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("invalid encrypted key")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(kid))
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt key: %w", err)
+	}
+	return string(plain), nil
+}
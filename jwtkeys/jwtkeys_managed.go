@@ -0,0 +1,255 @@
+// jwtkeys/jwtkeys_managed.go
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultRetain is how long a replaced key keeps verifying. It must be
+	// longer than ACCESS_TOKEN_TTL.
+	defaultRetain = 24 * time.Hour
+
+	// reloadInterval is how often every instance re-reads jwt_keys
+	reloadInterval = time.Minute
+
+	// publishAhead delays a new key's first signature until every instance
+	// (and JWKS consumers) has had time to pick it up
+	publishAhead = 5 * time.Minute
+)
+
+// manager keeps a keyset in sync with the jwt_keys table and rotates it
+type manager struct {
+	ks       *Keyset
+	rotation time.Duration // 0: never rotate
+	retain   time.Duration
+}
+
+func newManaged(alg string, rotation, retain time.Duration) (*Keyset, error) {
+	if err := configureSealing(); err != nil {
+		return nil, err
+	}
+	m := &manager{ks: newKeyset(alg, nil), rotation: rotation, retain: retain}
+	if alg == HS256 {
+		if err := m.seedSecrets(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.sync(); err != nil {
+		return nil, err
+	}
+	go m.loop()
+	return m.ks, nil
+}
+
+func (m *manager) loop() {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := m.sync(); err != nil {
+			log.Println("Error syncing JWT keys:", err)
+		}
+	}
+}
+
+// seedSecrets stores the static HS256 secrets as verify-only keys, so tokens
+// they signed keep working until they expire. Seeding is idempotent; a secret
+// already in the table keeps its original retirement.
+func (m *manager) seedSecrets(now time.Time) error {
+	for _, secret := range envSecrets() {
+		k := secretKey(secret, now)
+		k.RetireAt = now.Add(m.retain)
+		k.VerifyOnly = true
+		stored, err := sealKey(k.ID, base64.StdEncoding.EncodeToString([]byte(secret)))
+		if err != nil {
+			return err
+		}
+		if err := SeedKey(k, stored); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sync loads the live keys, adding a new one when the newest is due for
+// rotation (or there is none yet)
+func (m *manager) sync() error {
+	now := time.Now()
+	keys, err := FindLiveKeys(m.ks.alg, now)
+	if err != nil {
+		return err
+	}
+	if m.needsKey(keys, now) {
+		if keys, err = m.rotate(now); err != nil {
+			return err
+		}
+	}
+
+	m.ks.setKeys(keys)
+	_ = DeleteRetiredKeys(now)
+	return nil
+}
+
+// rotate adds a key while holding the jwt_keys lock, so instances that find
+// the same key due at once create only one between them. It returns the live
+// keys afterwards.
+func (m *manager) rotate(now time.Time) ([]Key, error) {
+	unlock, err := LockKeys(m.ks.alg)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Another instance may have rotated while we waited for the lock
+	keys, err := FindLiveKeys(m.ks.alg, now)
+	if err != nil || !m.needsKey(keys, now) {
+		return keys, err
+	}
+
+	activeAt := now.Add(publishAhead)
+	if newestSigningKey(keys) == nil {
+		activeAt = now // nothing to sign with in the meantime
+	}
+	k, encoded, err := generateKey(m.ks.alg, activeAt)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := sealKey(k.ID, encoded)
+	if err != nil {
+		return nil, err
+	}
+	if err := CreateKey(k, stored); err != nil {
+		return nil, err
+	}
+	if err := RetireKeysBefore(m.ks.alg, activeAt, activeAt.Add(m.retain)); err != nil {
+		return nil, err
+	}
+	log.Printf("Created JWT key %s (%s), signing from %s", k.ID, k.Alg, activeAt.Format(time.RFC3339))
+
+	return FindLiveKeys(m.ks.alg, now)
+}
+
+// newestSigningKey is the key with the latest ActiveAt that may sign
+func newestSigningKey(keys []Key) *Key {
+	var newest *Key
+	for i := range keys {
+		if keys[i].VerifyOnly {
+			continue
+		}
+		if newest == nil || keys[i].ActiveAt.After(newest.ActiveAt) {
+			newest = &keys[i]
+		}
+	}
+	return newest
+}
+
+func (m *manager) needsKey(keys []Key, now time.Time) bool {
+	newest := newestSigningKey(keys)
+	switch {
+	case newest == nil:
+		return true
+	case newest.ActiveAt.After(now):
+		return false // a rotation is already scheduled
+	case !newest.RetireAt.IsZero():
+		return true // the newest key is on its way out
+	default:
+		return m.rotation > 0 && now.Sub(newest.ActiveAt) >= m.rotation
+	}
+}
+
+// generateKey creates a key and its stored form
+func generateKey(alg string, activeAt time.Time) (Key, string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return Key{}, "", err
+	}
+	k := Key{ID: strings.ToLower(alg[:2]) + "-" + hex.EncodeToString(id), Alg: alg, ActiveAt: activeAt}
+
+	switch alg {
+	case HS256:
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return Key{}, "", err
+		}
+		k.signKey, k.verifyKey = secret, secret
+		return k, base64.StdEncoding.EncodeToString(secret), nil
+
+	case RS256:
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return Key{}, "", err
+		}
+		k.signKey, k.verifyKey = priv, &priv.PublicKey
+		encoded, err := encodePrivateKey(priv)
+		return k, encoded, err
+
+	case EdDSA:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return Key{}, "", err
+		}
+		k.signKey, k.verifyKey = priv, pub
+		encoded, err := encodePrivateKey(priv)
+		return k, encoded, err
+	}
+	return Key{}, "", fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+func encodePrivateKey(priv interface{}) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// decodeKey restores the signing and verification keys of a stored key
+func decodeKey(k *Key, stored string) error {
+	encoded, err := openKey(k.ID, stored)
+	if err != nil {
+		return err
+	}
+	if k.Alg == HS256 {
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return err
+		}
+		k.signKey, k.verifyKey = secret, secret
+		return nil
+	}
+
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return errors.New("invalid PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	switch priv := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.Alg != RS256 {
+			break
+		}
+		k.signKey, k.verifyKey = priv, &priv.PublicKey
+		return nil
+	case ed25519.PrivateKey:
+		if k.Alg != EdDSA {
+			break
+		}
+		k.signKey, k.verifyKey = priv, priv.Public()
+		return nil
+	}
+	return fmt.Errorf("key type does not match %s", k.Alg)
+}
@@ -0,0 +1,123 @@
+// jwtkeys/jwtkeys_repository.go
+package jwtkeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/JkD004/playarena-backend/db"
+)
+
+// keyLockTimeout is how many seconds LockKeys waits for another instance
+const keyLockTimeout = 10
+
+// FindLiveKeys loads the keys of an algorithm that have not retired yet
+func FindLiveKeys(alg string, now time.Time) ([]Key, error) {
+	query := `
+		SELECT kid, alg, private_key, active_at, retire_at, verify_only
+		FROM jwt_keys
+		WHERE alg = ? AND (retire_at IS NULL OR retire_at > ?)
+		ORDER BY active_at DESC
+	`
+	rows, err := db.DB.Query(query, alg, now)
+	if err != nil {
+		log.Println("Error finding JWT keys:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []Key
+	for rows.Next() {
+		var k Key
+		var encoded string
+		var retireAt sql.NullTime
+		if err := rows.Scan(&k.ID, &k.Alg, &encoded, &k.ActiveAt, &retireAt, &k.VerifyOnly); err != nil {
+			log.Println("Error scanning JWT key:", err)
+			return nil, err
+		}
+		if retireAt.Valid {
+			k.RetireAt = retireAt.Time
+		}
+		if err := decodeKey(&k, encoded); err != nil {
+			log.Printf("Skipping JWT key %s: %v", k.ID, err)
+			continue
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// CreateKey stores a new key
+func CreateKey(k Key, encoded string) error {
+	query := `INSERT INTO jwt_keys (kid, alg, private_key, active_at) VALUES (?, ?, ?, ?)`
+	_, err := db.DB.Exec(query, k.ID, k.Alg, encoded, k.ActiveAt)
+	if err != nil {
+		log.Println("Error creating JWT key:", err)
+	}
+	return err
+}
+
+// SeedKey stores a verify-only key unless one with its kid already exists
+func SeedKey(k Key, encoded string) error {
+	query := `
+		INSERT IGNORE INTO jwt_keys (kid, alg, private_key, active_at, retire_at, verify_only)
+		VALUES (?, ?, ?, ?, ?, TRUE)
+	`
+	_, err := db.DB.Exec(query, k.ID, k.Alg, encoded, k.ActiveAt, k.RetireAt)
+	if err != nil {
+		log.Println("Error seeding JWT key:", err)
+	}
+	return err
+}
+
+// LockKeys takes a database-wide lock on rotating keys of an algorithm and
+// returns the function that releases it. MySQL named locks belong to a
+// session, so the lock holds its own connection until released.
+func LockKeys(alg string) (func(), error) {
+	ctx := context.Background()
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		log.Println("Error getting connection for JWT key lock:", err)
+		return nil, err
+	}
+	name := "jwt_keys:" + alg
+	var got sql.NullInt64
+	if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, name, keyLockTimeout).Scan(&got); err != nil {
+		conn.Close()
+		log.Println("Error locking JWT keys:", err)
+		return nil, err
+	}
+	if got.Int64 != 1 {
+		conn.Close()
+		return nil, errors.New("timed out waiting for the JWT key lock")
+	}
+	return func() {
+		if _, err := conn.ExecContext(ctx, `DO RELEASE_LOCK(?)`, name); err != nil {
+			log.Println("Error unlocking JWT keys:", err)
+		}
+		conn.Close()
+	}, nil
+}
+
+// RetireKeysBefore schedules the retirement of every key replaced by one
+// that activates at activeAt
+func RetireKeysBefore(alg string, activeAt, retireAt time.Time) error {
+	query := `UPDATE jwt_keys SET retire_at = ? WHERE alg = ? AND active_at < ? AND retire_at IS NULL`
+	_, err := db.DB.Exec(query, retireAt, alg, activeAt)
+	if err != nil {
+		log.Println("Error retiring JWT keys:", err)
+	}
+	return err
+}
+
+// DeleteRetiredKeys removes keys that no longer verify anything
+func DeleteRetiredKeys(now time.Time) error {
+	_, err := db.DB.Exec(`DELETE FROM jwt_keys WHERE retire_at IS NOT NULL AND retire_at <= ?`, now)
+	if err != nil {
+		log.Println("Error deleting retired JWT keys:", err)
+	}
+	return err
+}
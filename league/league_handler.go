@@ -0,0 +1,247 @@
+// league/league_handler.go
+package league
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// -------------------------------------------------------
+// LEAGUES
+// -------------------------------------------------------
+
+// CreateLeagueHandler handles POST /api/v1/leagues
+func CreateLeagueHandler(c *gin.Context) {
+	var req CreateLeagueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+
+	l, err := CreateNewLeague(&req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, l)
+}
+
+// GetLeaguesHandler handles GET /api/v1/leagues
+func GetLeaguesHandler(c *gin.Context) {
+	leagues, err := GetAllLeagues()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch leagues"})
+		return
+	}
+	c.JSON(http.StatusOK, leagues)
+}
+
+// GetLeagueHandler handles GET /api/v1/leagues/:id (league + its seasons)
+func GetLeagueHandler(c *gin.Context) {
+	leagueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league ID"})
+		return
+	}
+
+	l, err := GetLeague(leagueID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+		return
+	}
+
+	seasons, err := GetLeagueSeasons(leagueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch seasons"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"league": l, "seasons": seasons})
+}
+
+// -------------------------------------------------------
+// SEASONS
+// -------------------------------------------------------
+
+// CreateSeasonHandler handles POST /api/v1/leagues/:id/seasons
+func CreateSeasonHandler(c *gin.Context) {
+	leagueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league ID"})
+		return
+	}
+
+	var req CreateSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	season, err := AddSeason(leagueID, &req, userID, userRole)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, season)
+}
+
+// AddSeasonTeamHandler handles POST /api/v1/seasons/:id/teams
+func AddSeasonTeamHandler(c *gin.Context) {
+	seasonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	var req AddTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request, 'team_id' is required"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	if err := AddTeamToSeason(seasonID, req.TeamID, userID, userRole); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Team added to season"})
+}
+
+// GetStandingsHandler handles GET /api/v1/seasons/:id/standings (public)
+func GetStandingsHandler(c *gin.Context) {
+	seasonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	table, err := GetStandings(seasonID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, table)
+}
+
+// -------------------------------------------------------
+// FIXTURES & RESULTS
+// -------------------------------------------------------
+
+// CreateFixtureHandler handles POST /api/v1/seasons/:id/fixtures
+func CreateFixtureHandler(c *gin.Context) {
+	seasonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	var req CreateFixtureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	f, err := ScheduleFixture(seasonID, &req, userID, userRole)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, f)
+}
+
+// GetFixturesHandler handles GET /api/v1/seasons/:id/fixtures (public)
+func GetFixturesHandler(c *gin.Context) {
+	seasonID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	fixtures, err := GetSeasonFixtures(seasonID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch fixtures"})
+		return
+	}
+	c.JSON(http.StatusOK, fixtures)
+}
+
+// SubmitResultHandler handles POST /api/v1/fixtures/:id/result
+func SubmitResultHandler(c *gin.Context) {
+	fixtureID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fixture ID"})
+		return
+	}
+
+	var req SubmitResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Both 'home_score' and 'away_score' are required"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+
+	completed, err := SubmitResult(fixtureID, *req.HomeScore, *req.AwayScore, userID)
+	if err != nil {
+		respondResultError(c, err)
+		return
+	}
+
+	if completed {
+		c.JSON(http.StatusOK, gin.H{"message": "Result recorded"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Result submitted, waiting for the other captain to confirm"})
+}
+
+// ConfirmResultHandler handles POST /api/v1/fixtures/:id/result/confirm
+func ConfirmResultHandler(c *gin.Context) {
+	fixtureID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fixture ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+
+	if err := ConfirmResult(fixtureID, userID); err != nil {
+		respondResultError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Result confirmed"})
+}
+
+// respondResultError maps a SubmitResult or ConfirmResult error to a status
+func respondResultError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrFixtureNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNegativeScore):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNotCaptain):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, ErrNoPendingResult), errors.Is(err, ErrResultAlreadyConfirmed), errors.Is(err, ErrAlreadyConfirmedByYou):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not record the result"})
+	}
+}
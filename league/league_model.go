@@ -0,0 +1,93 @@
+// league/league_model.go
+package league
+
+import "time"
+
+// League is a recurring competition run by an organizer.
+// The points fields configure how the standings table is scored.
+type League struct {
+	ID            int64     `json:"id"`
+	OrganizerID   int64     `json:"organizer_id"`
+	Name          string    `json:"name"`
+	SportCategory string    `json:"sport_category"`
+	PointsWin     int       `json:"points_win"`
+	PointsDraw    int       `json:"points_draw"`
+	PointsLoss    int       `json:"points_loss"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Season is one edition of a league (e.g. "Winter 2025")
+type Season struct {
+	ID        int64     `json:"id"`
+	LeagueID  int64     `json:"league_id"`
+	Name      string    `json:"name"`
+	StartDate string    `json:"start_date"` // YYYY-MM-DD
+	EndDate   string    `json:"end_date"`   // YYYY-MM-DD
+	Status    string    `json:"status"`     // 'upcoming', 'active', 'finished'
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Fixture is a scheduled match between two teams in a season
+type Fixture struct {
+	ID            int64     `json:"id"`
+	SeasonID      int64     `json:"season_id"`
+	HomeTeamID    int64     `json:"home_team_id"`
+	HomeTeamName  string    `json:"home_team_name"`
+	AwayTeamID    int64     `json:"away_team_id"`
+	AwayTeamName  string    `json:"away_team_name"`
+	BookingID     *int64    `json:"booking_id,omitempty"`
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	Status        string    `json:"status"` // 'scheduled', 'result_pending', 'completed'
+	HomeScore     *int      `json:"home_score,omitempty"`
+	AwayScore     *int      `json:"away_score,omitempty"`
+	SubmittedBy   *int64    `json:"submitted_by,omitempty"`
+	HomeConfirmed bool      `json:"home_confirmed"`
+	AwayConfirmed bool      `json:"away_confirmed"`
+}
+
+// StandingRow is one line of the computed league table
+type StandingRow struct {
+	Position     int    `json:"position"`
+	TeamID       int64  `json:"team_id"`
+	TeamName     string `json:"team_name"`
+	Played       int    `json:"played"`
+	Won          int    `json:"won"`
+	Drawn        int    `json:"drawn"`
+	Lost         int    `json:"lost"`
+	GoalsFor     int    `json:"goals_for"`
+	GoalsAgainst int    `json:"goals_against"`
+	GoalDiff     int    `json:"goal_difference"`
+	Points       int    `json:"points"`
+}
+
+// --- Request bodies ---
+
+type CreateLeagueRequest struct {
+	Name          string `json:"name" binding:"required"`
+	SportCategory string `json:"sport_category" binding:"required"`
+	PointsWin     *int   `json:"points_win"`
+	PointsDraw    *int   `json:"points_draw"`
+	PointsLoss    *int   `json:"points_loss"`
+}
+
+type CreateSeasonRequest struct {
+	Name      string `json:"name" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+}
+
+type AddTeamRequest struct {
+	TeamID int64 `json:"team_id" binding:"required"`
+}
+
+type CreateFixtureRequest struct {
+	HomeTeamID  int64     `json:"home_team_id" binding:"required"`
+	AwayTeamID  int64     `json:"away_team_id" binding:"required"`
+	BookingID   *int64    `json:"booking_id"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+}
+
+type SubmitResultRequest struct {
+	HomeScore *int `json:"home_score" binding:"required"`
+	AwayScore *int `json:"away_score" binding:"required"`
+}
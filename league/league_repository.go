@@ -0,0 +1,359 @@
+// league/league_repository.go
+package league
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/JkD004/playarena-backend/db"
+)
+
+// CreateLeague inserts a new league
+func CreateLeague(l *League) error {
+	query := `
+		INSERT INTO leagues (organizer_id, name, sport_category, points_win, points_draw, points_loss)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := db.DB.Exec(query, l.OrganizerID, l.Name, l.SportCategory, l.PointsWin, l.PointsDraw, l.PointsLoss)
+	if err != nil {
+		log.Println("Error inserting league:", err)
+		return err
+	}
+
+	id, _ := result.LastInsertId()
+	l.ID = id
+	return nil
+}
+
+// FindLeagueByID fetches a single league
+func FindLeagueByID(leagueID int64) (*League, error) {
+	query := `
+		SELECT id, organizer_id, name, sport_category, points_win, points_draw, points_loss, created_at
+		FROM leagues WHERE id = ?
+	`
+	var l League
+	err := db.DB.QueryRow(query, leagueID).Scan(
+		&l.ID, &l.OrganizerID, &l.Name, &l.SportCategory,
+		&l.PointsWin, &l.PointsDraw, &l.PointsLoss, &l.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// FindAllLeagues fetches every league, newest first
+func FindAllLeagues() ([]League, error) {
+	query := `
+		SELECT id, organizer_id, name, sport_category, points_win, points_draw, points_loss, created_at
+		FROM leagues
+		ORDER BY created_at DESC
+	`
+	rows, err := db.DB.Query(query)
+	if err != nil {
+		log.Println("Error querying leagues:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	leagues := make([]League, 0)
+	for rows.Next() {
+		var l League
+		if err := rows.Scan(
+			&l.ID, &l.OrganizerID, &l.Name, &l.SportCategory,
+			&l.PointsWin, &l.PointsDraw, &l.PointsLoss, &l.CreatedAt,
+		); err != nil {
+			log.Println("Error scanning league:", err)
+			continue
+		}
+		leagues = append(leagues, l)
+	}
+	return leagues, nil
+}
+
+// CreateSeason inserts a new season for a league
+func CreateSeason(s *Season) error {
+	query := `INSERT INTO league_seasons (league_id, name, start_date, end_date, status) VALUES (?, ?, ?, ?, 'upcoming')`
+	result, err := db.DB.Exec(query, s.LeagueID, s.Name, s.StartDate, s.EndDate)
+	if err != nil {
+		log.Println("Error inserting season:", err)
+		return err
+	}
+
+	id, _ := result.LastInsertId()
+	s.ID = id
+	s.Status = "upcoming"
+	return nil
+}
+
+// FindSeasonByID fetches a single season
+func FindSeasonByID(seasonID int64) (*Season, error) {
+	query := `
+		SELECT id, league_id, name, DATE_FORMAT(start_date, '%Y-%m-%d'), DATE_FORMAT(end_date, '%Y-%m-%d'), status, created_at
+		FROM league_seasons WHERE id = ?
+	`
+	var s Season
+	err := db.DB.QueryRow(query, seasonID).Scan(
+		&s.ID, &s.LeagueID, &s.Name, &s.StartDate, &s.EndDate, &s.Status, &s.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// FindSeasonsByLeagueID fetches all seasons of a league
+func FindSeasonsByLeagueID(leagueID int64) ([]Season, error) {
+	query := `
+		SELECT id, league_id, name, DATE_FORMAT(start_date, '%Y-%m-%d'), DATE_FORMAT(end_date, '%Y-%m-%d'), status, created_at
+		FROM league_seasons
+		WHERE league_id = ?
+		ORDER BY start_date DESC
+	`
+	rows, err := db.DB.Query(query, leagueID)
+	if err != nil {
+		log.Println("Error querying seasons:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := make([]Season, 0)
+	for rows.Next() {
+		var s Season
+		if err := rows.Scan(&s.ID, &s.LeagueID, &s.Name, &s.StartDate, &s.EndDate, &s.Status, &s.CreatedAt); err != nil {
+			log.Println("Error scanning season:", err)
+			continue
+		}
+		seasons = append(seasons, s)
+	}
+	return seasons, nil
+}
+
+// AddSeasonTeam registers a team as a participant of a season
+func AddSeasonTeam(seasonID, teamID int64) error {
+	query := `INSERT INTO season_teams (season_id, team_id) VALUES (?, ?)`
+	_, err := db.DB.Exec(query, seasonID, teamID)
+	if err != nil {
+		log.Println("Error adding team to season:", err)
+		return err
+	}
+	return nil
+}
+
+// IsSeasonTeam checks if a team participates in a season
+func IsSeasonTeam(seasonID, teamID int64) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM season_teams WHERE season_id = ? AND team_id = ?`
+	err := db.DB.QueryRow(query, seasonID, teamID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindSeasonTeams returns the (team_id, name) pairs taking part in a season
+func FindSeasonTeams(seasonID int64) (map[int64]string, error) {
+	query := `
+		SELECT t.id, t.name
+		FROM season_teams st
+		JOIN teams t ON st.team_id = t.id
+		WHERE st.season_id = ?
+	`
+	rows, err := db.DB.Query(query, seasonID)
+	if err != nil {
+		log.Println("Error querying season teams:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			continue
+		}
+		teams[id] = name
+	}
+	return teams, nil
+}
+
+// CreateFixture inserts a new fixture
+func CreateFixture(f *Fixture) error {
+	query := `
+		INSERT INTO league_fixtures (season_id, home_team_id, away_team_id, booking_id, scheduled_at, status)
+		VALUES (?, ?, ?, ?, ?, 'scheduled')
+	`
+	var bookingID sql.NullInt64
+	if f.BookingID != nil {
+		bookingID = sql.NullInt64{Int64: *f.BookingID, Valid: true}
+	}
+
+	result, err := db.DB.Exec(query, f.SeasonID, f.HomeTeamID, f.AwayTeamID, bookingID, f.ScheduledAt)
+	if err != nil {
+		log.Println("Error inserting fixture:", err)
+		return err
+	}
+
+	id, _ := result.LastInsertId()
+	f.ID = id
+	f.Status = "scheduled"
+	return nil
+}
+
+const fixtureColumns = `
+	f.id, f.season_id, f.home_team_id, ht.name, f.away_team_id, awt.name,
+	f.booking_id, f.scheduled_at, f.status, f.home_score, f.away_score,
+	f.submitted_by, f.home_confirmed, f.away_confirmed
+`
+
+const fixtureJoins = `
+	FROM league_fixtures f
+	JOIN teams ht ON f.home_team_id = ht.id
+	JOIN teams awt ON f.away_team_id = awt.id
+`
+
+// FindFixtureByID fetches a single fixture with team names
+func FindFixtureByID(fixtureID int64) (*Fixture, error) {
+	query := `SELECT ` + fixtureColumns + fixtureJoins + ` WHERE f.id = ?`
+	rows, err := db.DB.Query(query, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanFixture(rows)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// FindFixtureForUpdate fetches a fixture inside a transaction, locking it
+// until the transaction ends
+func FindFixtureForUpdate(tx *sql.Tx, fixtureID int64) (*Fixture, error) {
+	query := `SELECT ` + fixtureColumns + fixtureJoins + ` WHERE f.id = ? FOR UPDATE`
+	rows, err := tx.Query(query, fixtureID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanFixture(rows)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// FindFixturesBySeasonID fetches all fixtures of a season in kick-off order
+func FindFixturesBySeasonID(seasonID int64) ([]Fixture, error) {
+	query := `SELECT ` + fixtureColumns + fixtureJoins + ` WHERE f.season_id = ? ORDER BY f.scheduled_at ASC`
+	rows, err := db.DB.Query(query, seasonID)
+	if err != nil {
+		log.Println("Error querying fixtures:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	fixtures := make([]Fixture, 0)
+	for rows.Next() {
+		f, err := scanFixture(rows)
+		if err != nil {
+			log.Println("Error scanning fixture:", err)
+			continue
+		}
+		fixtures = append(fixtures, *f)
+	}
+	return fixtures, nil
+}
+
+// SaveFixtureResult stores a submitted score and resets confirmations
+// so that only the submitting side is marked as confirmed. A result
+// confirmed by both sides at once completes the fixture.
+func SaveFixtureResult(tx *sql.Tx, fixtureID int64, homeScore, awayScore int, submittedBy int64, homeConfirmed, awayConfirmed bool) error {
+	status := "result_pending"
+	if homeConfirmed && awayConfirmed {
+		status = "completed"
+	}
+	query := `
+		UPDATE league_fixtures
+		SET home_score = ?, away_score = ?, submitted_by = ?,
+		    home_confirmed = ?, away_confirmed = ?, status = ?
+		WHERE id = ? AND status != 'completed'
+	`
+	result, err := tx.Exec(query, homeScore, awayScore, submittedBy, homeConfirmed, awayConfirmed, status, fixtureID)
+	if err != nil {
+		log.Println("Error saving fixture result:", err)
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("fixture not found or result already confirmed")
+	}
+	return nil
+}
+
+// ConfirmFixtureResult marks one side as confirmed and completes the
+// fixture once both captains have agreed. It reports whether this call
+// completed the fixture.
+func ConfirmFixtureResult(tx *sql.Tx, fixtureID int64, home bool) (bool, error) {
+	column := "away_confirmed"
+	if home {
+		column = "home_confirmed"
+	}
+
+	query := `UPDATE league_fixtures SET ` + column + ` = TRUE WHERE id = ? AND status = 'result_pending'`
+	result, err := tx.Exec(query, fixtureID)
+	if err != nil {
+		log.Println("Error confirming fixture result:", err)
+		return false, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return false, errors.New("no pending result to confirm")
+	}
+
+	result, err = tx.Exec(`
+		UPDATE league_fixtures SET status = 'completed'
+		WHERE id = ? AND status = 'result_pending' AND home_confirmed = TRUE AND away_confirmed = TRUE
+	`, fixtureID)
+	if err != nil {
+		log.Println("Error completing fixture:", err)
+		return false, err
+	}
+	rowsAffected, _ = result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+func scanFixture(rows *sql.Rows) (*Fixture, error) {
+	var f Fixture
+	var bookingID, submittedBy sql.NullInt64
+	var homeScore, awayScore sql.NullInt64
+
+	err := rows.Scan(
+		&f.ID, &f.SeasonID, &f.HomeTeamID, &f.HomeTeamName, &f.AwayTeamID, &f.AwayTeamName,
+		&bookingID, &f.ScheduledAt, &f.Status, &homeScore, &awayScore,
+		&submittedBy, &f.HomeConfirmed, &f.AwayConfirmed,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if bookingID.Valid {
+		f.BookingID = &bookingID.Int64
+	}
+	if submittedBy.Valid {
+		f.SubmittedBy = &submittedBy.Int64
+	}
+	if homeScore.Valid {
+		h := int(homeScore.Int64)
+		f.HomeScore = &h
+	}
+	if awayScore.Valid {
+		a := int(awayScore.Int64)
+		f.AwayScore = &a
+	}
+	return &f, nil
+}
@@ -0,0 +1,492 @@
+// league/league_service.go
+package league
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/JkD004/playarena-backend/booking"
+	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/notification"
+	"github.com/JkD004/playarena-backend/rating"
+	"github.com/JkD004/playarena-backend/team"
+)
+
+// Default points rules used when the organizer doesn't set their own
+const (
+	DefaultPointsWin  = 3
+	DefaultPointsDraw = 1
+	DefaultPointsLoss = 0
+)
+
+// Errors from submitting and confirming fixture results
+var (
+	ErrFixtureNotFound        = errors.New("fixture not found")
+	ErrNegativeScore          = errors.New("scores cannot be negative")
+	ErrNotCaptain             = errors.New("only the team captains can submit or confirm a result")
+	ErrNoPendingResult        = errors.New("no pending result to confirm")
+	ErrResultAlreadyConfirmed = errors.New("result already confirmed")
+	ErrAlreadyConfirmedByYou  = errors.New("you have already confirmed this result")
+)
+
+// findFixture loads a fixture, telling a missing one from a failed lookup
+func findFixture(fixtureID int64) (*Fixture, error) {
+	f, err := FindFixtureByID(fixtureID)
+	if err == sql.ErrNoRows {
+		return nil, ErrFixtureNotFound
+	}
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	return f, nil
+}
+
+// CreateNewLeague validates the points rules and saves the league
+func CreateNewLeague(req *CreateLeagueRequest, organizerID int64) (*League, error) {
+	l := &League{
+		OrganizerID:   organizerID,
+		Name:          req.Name,
+		SportCategory: req.SportCategory,
+		PointsWin:     DefaultPointsWin,
+		PointsDraw:    DefaultPointsDraw,
+		PointsLoss:    DefaultPointsLoss,
+	}
+	if req.PointsWin != nil {
+		l.PointsWin = *req.PointsWin
+	}
+	if req.PointsDraw != nil {
+		l.PointsDraw = *req.PointsDraw
+	}
+	if req.PointsLoss != nil {
+		l.PointsLoss = *req.PointsLoss
+	}
+
+	if l.PointsWin < l.PointsDraw || l.PointsDraw < l.PointsLoss {
+		return nil, errors.New("points rules must satisfy win >= draw >= loss")
+	}
+
+	if err := CreateLeague(l); err != nil {
+		return nil, errors.New("could not create league")
+	}
+	return l, nil
+}
+
+// GetAllLeagues is the service-layer function
+func GetAllLeagues() ([]League, error) {
+	return FindAllLeagues()
+}
+
+// GetLeague is the service-layer function
+func GetLeague(leagueID int64) (*League, error) {
+	return FindLeagueByID(leagueID)
+}
+
+// GetLeagueSeasons is the service-layer function
+func GetLeagueSeasons(leagueID int64) ([]Season, error) {
+	return FindSeasonsByLeagueID(leagueID)
+}
+
+// verifyOrganizer makes sure only the league organizer (or an admin) manages it
+func verifyOrganizer(l *League, userID int64, userRole string) error {
+	if userRole == "admin" || l.OrganizerID == userID {
+		return nil
+	}
+	return errors.New("only the league organizer can manage this league")
+}
+
+// leagueForSeason resolves the season and its parent league
+func leagueForSeason(seasonID int64) (*Season, *League, error) {
+	season, err := FindSeasonByID(seasonID)
+	if err != nil {
+		return nil, nil, errors.New("season not found")
+	}
+	l, err := FindLeagueByID(season.LeagueID)
+	if err != nil {
+		return nil, nil, errors.New("league not found")
+	}
+	return season, l, nil
+}
+
+// AddSeason creates a new season in a league
+func AddSeason(leagueID int64, req *CreateSeasonRequest, userID int64, userRole string) (*Season, error) {
+	l, err := FindLeagueByID(leagueID)
+	if err != nil {
+		return nil, errors.New("league not found")
+	}
+	if err := verifyOrganizer(l, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("start_date must be YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.New("end_date must be YYYY-MM-DD")
+	}
+	if !end.After(start) {
+		return nil, errors.New("end_date must be after start_date")
+	}
+
+	season := &Season{
+		LeagueID:  leagueID,
+		Name:      req.Name,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	}
+	if err := CreateSeason(season); err != nil {
+		return nil, errors.New("could not create season")
+	}
+	return season, nil
+}
+
+// AddTeamToSeason enrolls a team in a season
+func AddTeamToSeason(seasonID, teamID int64, userID int64, userRole string) error {
+	_, l, err := leagueForSeason(seasonID)
+	if err != nil {
+		return err
+	}
+	if err := verifyOrganizer(l, userID, userRole); err != nil {
+		return err
+	}
+
+	t, err := team.GetTeamByID(teamID)
+	if err != nil {
+		return errors.New("team not found")
+	}
+
+	already, err := IsSeasonTeam(seasonID, teamID)
+	if err != nil {
+		return errors.New("database error")
+	}
+	if already {
+		return errors.New("team is already part of this season")
+	}
+
+	if err := AddSeasonTeam(seasonID, teamID); err != nil {
+		return errors.New("failed to add team to season")
+	}
+
+	_ = notification.CreateNotification(t.OwnerID, "Your team "+t.Name+" has been added to the league "+l.Name+".", "info")
+	return nil
+}
+
+// ScheduleFixture creates a fixture between two participating teams.
+// If a booking is supplied it must be an active booking made for that slot.
+func ScheduleFixture(seasonID int64, req *CreateFixtureRequest, userID int64, userRole string) (*Fixture, error) {
+	_, l, err := leagueForSeason(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyOrganizer(l, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	if req.HomeTeamID == req.AwayTeamID {
+		return nil, errors.New("a team cannot play itself")
+	}
+	for _, teamID := range []int64{req.HomeTeamID, req.AwayTeamID} {
+		ok, err := IsSeasonTeam(seasonID, teamID)
+		if err != nil {
+			return nil, errors.New("database error")
+		}
+		if !ok {
+			return nil, errors.New("both teams must be registered for this season")
+		}
+	}
+
+	// Results need a confirmation from each side, which one captain can't give
+	home, err := team.GetTeamByID(req.HomeTeamID)
+	if err != nil {
+		return nil, errors.New("home team not found")
+	}
+	away, err := team.GetTeamByID(req.AwayTeamID)
+	if err != nil {
+		return nil, errors.New("away team not found")
+	}
+	if home.OwnerID == away.OwnerID {
+		return nil, errors.New("both teams have the same captain")
+	}
+
+	if req.BookingID != nil {
+		b, err := booking.FindBookingByID(*req.BookingID)
+		if err != nil {
+			return nil, errors.New("linked booking not found")
+		}
+		if b.Status == "canceled" {
+			return nil, errors.New("linked booking has been canceled")
+		}
+		if !b.StartTime.Equal(req.ScheduledAt) {
+			return nil, errors.New("fixture time must match the linked booking's start time")
+		}
+	}
+
+	f := &Fixture{
+		SeasonID:    seasonID,
+		HomeTeamID:  req.HomeTeamID,
+		AwayTeamID:  req.AwayTeamID,
+		BookingID:   req.BookingID,
+		ScheduledAt: req.ScheduledAt,
+	}
+	if err := CreateFixture(f); err != nil {
+		return nil, errors.New("could not create fixture")
+	}
+	return f, nil
+}
+
+// GetSeasonFixtures is the service-layer function
+func GetSeasonFixtures(seasonID int64) ([]Fixture, error) {
+	return FindFixturesBySeasonID(seasonID)
+}
+
+// captainSide reports whether the user captains the home or away team of a fixture.
+// Team captains are the team owners.
+func captainSide(f *Fixture, userID int64) (isHome bool, isAway bool, err error) {
+	home, err := team.GetTeamByID(f.HomeTeamID)
+	if err != nil {
+		return false, false, errors.New("home team not found")
+	}
+	away, err := team.GetTeamByID(f.AwayTeamID)
+	if err != nil {
+		return false, false, errors.New("away team not found")
+	}
+	return home.OwnerID == userID, away.OwnerID == userID, nil
+}
+
+// SubmitResult records a score from one of the captains. The submitting
+// side is confirmed automatically; the other captain must confirm it.
+// A user who captains both teams (a team changed hands after the fixture
+// was scheduled) confirms both sides, which completes the fixture at once;
+// the returned flag reports that.
+func SubmitResult(fixtureID int64, homeScore, awayScore int, userID int64) (bool, error) {
+	if homeScore < 0 || awayScore < 0 {
+		return false, ErrNegativeScore
+	}
+
+	f, err := findFixture(fixtureID)
+	if err != nil {
+		return false, err
+	}
+	if f.Status == "completed" {
+		return false, ErrResultAlreadyConfirmed
+	}
+
+	isHome, isAway, err := captainSide(f, userID)
+	if err != nil {
+		return false, err
+	}
+	if !isHome && !isAway {
+		return false, ErrNotCaptain
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return false, errors.New("database error")
+	}
+	defer tx.Rollback()
+
+	if err := SaveFixtureResult(tx, fixtureID, homeScore, awayScore, userID, isHome, isAway); err != nil {
+		return false, errors.New("could not record result")
+	}
+	if isHome && isAway {
+		f.HomeScore, f.AwayScore = &homeScore, &awayScore
+		if err := recordRatings(tx, f); err != nil {
+			log.Printf("Error updating ratings for fixture %d: %v", fixtureID, err)
+			return false, errors.New("could not record result")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, errors.New("could not record result")
+	}
+	if isHome && isAway {
+		log.Printf("Fixture %d result submitted by the captain of both teams (user %d)", fixtureID, userID)
+		return true, nil
+	}
+
+	// Ask the opposing captain to confirm
+	opponentID := f.AwayTeamID
+	if isAway {
+		opponentID = f.HomeTeamID
+	}
+	if opponent, err := team.GetTeamByID(opponentID); err == nil {
+		_ = notification.CreateNotification(opponent.OwnerID, "A result was submitted for "+f.HomeTeamName+" vs "+f.AwayTeamName+". Please confirm it.", "info")
+	}
+	return false, nil
+}
+
+// ConfirmResult lets the opposing captain accept a submitted result.
+// Completing the fixture and updating player ratings happen in one
+// transaction, so a result never counts without its ratings (or twice).
+func ConfirmResult(fixtureID int64, userID int64) error {
+	f, err := findFixture(fixtureID)
+	if err != nil {
+		return err
+	}
+
+	isHome, isAway, err := captainSide(f, userID)
+	if err != nil {
+		return err
+	}
+	if !isHome && !isAway {
+		return ErrNotCaptain
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return errors.New("database error")
+	}
+	defer tx.Rollback()
+
+	// Lock the fixture so the score can't change while it is rated
+	f, err = FindFixtureForUpdate(tx, fixtureID)
+	if err == sql.ErrNoRows {
+		return ErrFixtureNotFound
+	}
+	if err != nil {
+		return errors.New("database error")
+	}
+	if f.Status == "completed" {
+		return ErrResultAlreadyConfirmed
+	}
+	if f.Status != "result_pending" {
+		return ErrNoPendingResult
+	}
+
+	// Confirm whichever side this captain hasn't confirmed yet
+	home := isHome && !f.HomeConfirmed
+	if !home && !(isAway && !f.AwayConfirmed) {
+		return ErrAlreadyConfirmedByYou
+	}
+
+	completed, err := ConfirmFixtureResult(tx, fixtureID, home)
+	if err != nil {
+		return errors.New("could not record result")
+	}
+	// Once both captains agree, feed the result into player ratings
+	if completed {
+		if err := recordRatings(tx, f); err != nil {
+			log.Printf("Error updating ratings for fixture %d: %v", fixtureID, err)
+			return errors.New("could not record result")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.New("could not record result")
+	}
+	log.Printf("Fixture %d result confirmed by user %d", fixtureID, userID)
+	return nil
+}
+
+// recordRatings applies a completed fixture to the players' sport ratings
+func recordRatings(tx *sql.Tx, f *Fixture) error {
+	_, l, err := leagueForSeason(f.SeasonID)
+	if err != nil {
+		return err
+	}
+
+	homePlayers, err := team.GetJoinedMemberIDs(f.HomeTeamID)
+	if err != nil {
+		return err
+	}
+	awayPlayers, err := team.GetJoinedMemberIDs(f.AwayTeamID)
+	if err != nil {
+		return err
+	}
+	// A side without players has nobody to rate; the result still stands
+	if len(homePlayers) == 0 || len(awayPlayers) == 0 {
+		return nil
+	}
+
+	return rating.RecordMatch(tx, l.SportCategory, homePlayers, awayPlayers, *f.HomeScore, *f.AwayScore, &f.ID)
+}
+
+// GetStandings builds the league table for a season
+func GetStandings(seasonID int64) ([]StandingRow, error) {
+	_, l, err := leagueForSeason(seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := FindSeasonTeams(seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures, err := FindFixturesBySeasonID(seasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeStandings(teams, fixtures, l.PointsWin, l.PointsDraw, l.PointsLoss), nil
+}
+
+// ComputeStandings tallies completed fixtures into a sorted table.
+// Ties are broken by goal difference, then goals scored, then team name.
+func ComputeStandings(teams map[int64]string, fixtures []Fixture, pointsWin, pointsDraw, pointsLoss int) []StandingRow {
+	rows := make(map[int64]*StandingRow, len(teams))
+	for id, name := range teams {
+		rows[id] = &StandingRow{TeamID: id, TeamName: name}
+	}
+
+	for _, f := range fixtures {
+		if f.Status != "completed" || f.HomeScore == nil || f.AwayScore == nil {
+			continue
+		}
+		home, okHome := rows[f.HomeTeamID]
+		away, okAway := rows[f.AwayTeamID]
+		if !okHome || !okAway {
+			continue
+		}
+
+		hs, as := *f.HomeScore, *f.AwayScore
+		home.Played++
+		away.Played++
+		home.GoalsFor += hs
+		home.GoalsAgainst += as
+		away.GoalsFor += as
+		away.GoalsAgainst += hs
+
+		switch {
+		case hs > as:
+			home.Won++
+			away.Lost++
+		case hs < as:
+			away.Won++
+			home.Lost++
+		default:
+			home.Drawn++
+			away.Drawn++
+		}
+	}
+
+	table := make([]StandingRow, 0, len(rows))
+	for _, r := range rows {
+		r.GoalDiff = r.GoalsFor - r.GoalsAgainst
+		r.Points = r.Won*pointsWin + r.Drawn*pointsDraw + r.Lost*pointsLoss
+		table = append(table, *r)
+	}
+
+	sort.Slice(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.GoalDiff != b.GoalDiff {
+			return a.GoalDiff > b.GoalDiff
+		}
+		if a.GoalsFor != b.GoalsFor {
+			return a.GoalsFor > b.GoalsFor
+		}
+		return a.TeamName < b.TeamName
+	})
+
+	for i := range table {
+		table[i].Position = i + 1
+	}
+	return table
+}
@@ -0,0 +1,87 @@
+// mailer/mailer.go
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends transactional email (verification links, password resets)
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// devMode reports whether APP_ENV=development, which allows the log driver
+// to stand in for a real one
+func devMode() bool {
+	return os.Getenv("APP_ENV") == "development"
+}
+
+// NewFromEnv builds the mailer selected by MAIL_DRIVER:
+//
+//	smtp   - sends through SMTP_HOST:SMTP_PORT, authenticating when SMTP_USER
+//	         is set; SMTP_HOST is required outside APP_ENV=development, where
+//	         it defaults to localhost:1025 (a local MailHog/Mailpit)
+//	log    - writes messages to the log instead of sending them; bodies
+//	         (which hold verification and reset links) are only written
+//	         when APP_ENV=development
+//	memory - keeps messages in memory (tests)
+//
+// Without MAIL_DRIVER, log is used when APP_ENV=development and startup fails
+// otherwise, so a deployment can't silently drop mail. MAIL_FROM sets the
+// sender address.
+func NewFromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "PlayArena <no-reply@playarena.local>"
+	}
+
+	driver := os.Getenv("MAIL_DRIVER")
+	if driver == "" {
+		if !devMode() {
+			return nil, errors.New("MAIL_DRIVER is not set (use APP_ENV=development to log mail instead)")
+		}
+		driver = "log"
+	}
+
+	switch driver {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			if !devMode() {
+				return nil, errors.New("SMTP_HOST is not set")
+			}
+			host = "localhost"
+		}
+		port := 1025
+		if raw := os.Getenv("SMTP_PORT"); raw != "" {
+			p, err := strconv.Atoi(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SMTP_PORT %q", raw)
+			}
+			port = p
+		}
+		return NewSMTP(host, port, os.Getenv("SMTP_USER"), os.Getenv("SMTP_PASSWORD"), from), nil
+	case "log":
+		if !devMode() {
+			log.Println("⚠️  MAIL_DRIVER=log outside development: mail is not sent and bodies are not logged")
+		}
+		return NewLog(from, devMode()), nil
+	case "memory":
+		log.Println("⚠️  Using in-memory mailer, mail is kept in memory and never sent")
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q", driver)
+	}
+}
@@ -0,0 +1,28 @@
+// mailer/mailer_log.go
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// Log writes messages to the log instead of sending them. Useful in
+// development, where verification links can be copied from the output.
+// Bodies carry those links, so they are only written when showBody is set.
+type Log struct {
+	from     string
+	showBody bool
+}
+
+func NewLog(from string, showBody bool) *Log {
+	return &Log{from: from, showBody: showBody}
+}
+
+func (l *Log) Send(ctx context.Context, msg Message) error {
+	if !l.showBody {
+		log.Printf("📧 Mail from %s to %s: %s (body not logged)", l.from, msg.To, msg.Subject)
+		return nil
+	}
+	log.Printf("📧 Mail from %s to %s: %s\n%s", l.from, msg.To, msg.Subject, msg.Body)
+	return nil
+}
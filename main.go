@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"github.com/cloudinary/cloudinary-go/v2"
 
 	"github.com/JkD004/playarena-backend/api"
 	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/jwtkeys"
+	"github.com/JkD004/playarena-backend/mailer"
+	"github.com/JkD004/playarena-backend/sms"
+	"github.com/JkD004/playarena-backend/storage"
 	"github.com/JkD004/playarena-backend/venue"
 	"github.com/JkD004/playarena-backend/user"
 )
@@ -25,14 +30,39 @@ func main() {
 	// Initialize DB
 	db.InitDB()
 
-	// Initialize Cloudinary
-	cld, err := cloudinary.New()
+	// Initialize JWT signing keys (JWT_ALG=HS256|RS256|EdDSA)
+	keys, err := jwtkeys.NewFromEnv()
 	if err != nil {
-		log.Fatalf("❌ Failed to initialize Cloudinary: %v", err)
+		log.Fatalf("❌ Failed to initialize JWT keys: %v", err)
 	}
+	jwtkeys.Use(keys)
 
-	venue.SetCloudinary(cld)
-	user.SetCloudinary(cld)
+	// Initialize media storage (STORAGE_DRIVER=cloudinary|local|memory)
+	store, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize storage: %v", err)
+	}
+
+	// Deletes are retried in case the provider is briefly unavailable;
+	// ones that still fail are kept and swept up in the background
+	media := storage.WithRetries(store, 4, 500*time.Millisecond)
+	venue.SetStorage(media)
+	user.SetStorage(media)
+	go storage.RetryPendingDeletes(context.Background(), media, 5*time.Minute)
+
+	// Initialize outgoing mail (MAIL_DRIVER=smtp|log|memory; log needs APP_ENV=development)
+	mail, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize mailer: %v", err)
+	}
+	user.SetMailer(mail)
+
+	// Initialize SMS for one-time codes (SMS_DRIVER=twilio|log|memory; log needs APP_ENV=development)
+	texts, err := sms.NewFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize SMS: %v", err)
+	}
+	user.SetSMS(texts)
 
 	// Setup Gin Router
 	router := gin.Default()
@@ -41,9 +71,15 @@ func main() {
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true 
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match"}
+	config.ExposeHeaders = []string{"X-Next-Cursor", "ETag"}
 	router.Use(cors.New(config))
 
+	// Serve locally stored uploads
+	if local, ok := store.(*storage.Local); ok {
+		router.Static(local.BaseURL, local.Dir)
+	}
+
 	// 🟢 ROOT ROUTE (Fix 404)
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
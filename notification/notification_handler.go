@@ -26,9 +26,12 @@ func MarkReadHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	
-	// TODO: Verify the notification belongs to the user
-	_ = MarkAsRead(id) // We ignore error for simplicity here
-	
+
+	// Only the notification's owner gets here (policy.NotificationRead)
+	if err := MarkAsRead(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Marked as read"})
 }
\ No newline at end of file
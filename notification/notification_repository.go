@@ -52,4 +52,14 @@ func MarkAsRead(notificationID int64) error {
 	query := `UPDATE notifications SET is_read = TRUE WHERE id = ?`
 	_, err := db.DB.Exec(query, notificationID)
 	return err
-}
\ No newline at end of file
+}
+// FindNotificationByID fetches a single notification
+func FindNotificationByID(notificationID int64) (*Notification, error) {
+	query := `SELECT id, user_id, message, type, is_read, created_at FROM notifications WHERE id = ?`
+	var n Notification
+	err := db.DB.QueryRow(query, notificationID).Scan(&n.ID, &n.UserID, &n.Message, &n.Type, &n.IsRead, &n.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
@@ -0,0 +1,173 @@
+// policy/policy.go
+package policy
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Permission names an action on a kind of resource, e.g. "booking:pay"
+type Permission string
+
+// Subject is the authenticated user a decision is made for
+type Subject struct {
+	UserID        int64
+	Role          string
+	EmailVerified bool
+}
+
+// Scope says how a permission relates the subject to the resource
+type Scope int
+
+const (
+	// ScopeRole only checks the subject's role
+	ScopeRole Scope = iota
+	// ScopeOwn requires the resource to belong to the subject
+	ScopeOwn
+	// ScopeVenueOwner requires the subject to own the resource's venue
+	ScopeVenueOwner
+	// ScopeVenueStaff allows the venue's owner and active staff holding
+	// Rule.StaffPermission
+	ScopeVenueStaff
+	// ScopeTeamMember requires the subject to have joined the resource's team
+	ScopeTeamMember
+)
+
+// Rule is the policy for one permission
+type Rule struct {
+	Roles         []string // roles that may attempt the action at all
+	VerifiedEmail bool     // the subject must have confirmed their email
+
+	// Resource is the kind of object the request's :id refers to ("" when the
+	// permission isn't tied to one). Scope decides how it must relate to the
+	// subject; admins bypass the resource check when AdminOverride is set.
+	Resource        string
+	Scope           Scope
+	StaffPermission string // venue staff permission for ScopeVenueStaff
+	AdminOverride   bool
+}
+
+// Resource is what policy needs to know about the object a request targets.
+// Zero fields are unknown / not applicable.
+type Resource struct {
+	OwnerID int64 // user the resource belongs to
+	VenueID int64 // venue the resource belongs to
+	TeamID  int64 // team the resource belongs to
+}
+
+// Loader fetches a resource of one kind by ID. It returns ErrNotFound when
+// there is no such resource.
+type Loader func(id int64) (*Resource, error)
+
+// VenueAccess reports whether a user owns a venue (staffPermission == "") or
+// is its owner or active staff with staffPermission.
+type VenueAccess func(venueID, userID int64, staffPermission string) (bool, error)
+
+// TeamMembership reports whether a user has joined a team
+type TeamMembership func(teamID, userID int64) (bool, error)
+
+var (
+	ErrForbidden = errors.New("you do not have permission")
+	ErrNotFound  = errors.New("not found")
+
+	ErrEmailNotVerified = errors.New("email address is not verified")
+)
+
+var (
+	loaders     = map[string]Loader{}
+	venueAccess VenueAccess
+	teamMember  TeamMembership
+)
+
+// RegisterLoader sets the loader for a resource kind
+func RegisterLoader(kind string, l Loader) {
+	loaders[kind] = l
+}
+
+// SetVenueAccess sets the check used by the venue scopes
+func SetVenueAccess(f VenueAccess) {
+	venueAccess = f
+}
+
+// SetTeamMembership sets the check used by ScopeTeamMember
+func SetTeamMembership(f TeamMembership) {
+	teamMember = f
+}
+
+// RuleFor returns the rule of a permission; unknown permissions panic, so a
+// typo in the route table fails at startup rather than opening a route.
+func RuleFor(p Permission) Rule {
+	rule, ok := Rules[p]
+	if !ok {
+		panic(fmt.Sprintf("policy: unknown permission %q", p))
+	}
+	return rule
+}
+
+// HasRole reports whether the subject's role may attempt p at all
+func HasRole(s Subject, p Permission) bool {
+	for _, role := range RuleFor(p).Roles {
+		if s.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Can decides whether the subject may perform p on the resource with the
+// given ID (ignored for permissions without a resource). It returns nil,
+// ErrForbidden, ErrEmailNotVerified, ErrNotFound, or the error of a failed
+// lookup.
+func Can(s Subject, p Permission, resourceID int64) error {
+	rule := RuleFor(p)
+	if !HasRole(s, p) {
+		return ErrForbidden
+	}
+	if rule.VerifiedEmail && !s.EmailVerified {
+		return ErrEmailNotVerified
+	}
+	if rule.Resource == "" {
+		return nil
+	}
+
+	load, ok := loaders[rule.Resource]
+	if !ok {
+		return fmt.Errorf("policy: no loader for resource %q", rule.Resource)
+	}
+	res, err := load(resourceID)
+	if err != nil {
+		return err
+	}
+	if rule.AdminOverride && s.Role == "admin" {
+		return nil
+	}
+
+	var allowed bool
+	switch rule.Scope {
+	case ScopeRole:
+		allowed = true
+	case ScopeOwn:
+		allowed = res.OwnerID != 0 && res.OwnerID == s.UserID
+	case ScopeVenueOwner, ScopeVenueStaff:
+		staffPermission := ""
+		if rule.Scope == ScopeVenueStaff {
+			staffPermission = rule.StaffPermission
+		}
+		if venueAccess == nil || res.VenueID == 0 {
+			return ErrForbidden
+		}
+		allowed, err = venueAccess(res.VenueID, s.UserID, staffPermission)
+	case ScopeTeamMember:
+		if teamMember == nil || res.TeamID == 0 {
+			return ErrForbidden
+		}
+		allowed, err = teamMember(res.TeamID, s.UserID)
+	}
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrForbidden
+	}
+	return nil
+}
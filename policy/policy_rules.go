@@ -0,0 +1,178 @@
+// policy/policy_rules.go
+package policy
+
+// Role lists used by the rules below
+var (
+	anyUser    = []string{"player", "owner", "admin"}
+	ownerAdmin = []string{"owner", "admin"}
+	adminOnly  = []string{"admin"}
+)
+
+// Resource kinds, each backed by a Loader registered at startup
+const (
+	ResourceVenue        = "venue"
+	ResourcePhoto        = "photo" // venue photo
+	ResourceBooking      = "booking"
+	ResourceReview       = "review"
+	ResourceNotification = "notification"
+	ResourceTeam         = "team"
+)
+
+// Permissions. Venue staff are ordinary accounts, so venue actions that staff
+// can perform are open to every role and decided per venue.
+const (
+	// Admin
+	VenueListAll   Permission = "venue:list_all"
+	VenueDecide    Permission = "venue:decide"
+	BookingListAll Permission = "booking:list_all"
+	ReviewModerate Permission = "review:moderate"
+	RevisionDecide Permission = "revision:decide"
+	TaxonomyManage Permission = "taxonomy:manage"
+	StatsPlatform  Permission = "stats:platform"
+	UserManage     Permission = "user:manage" // role and account status
+
+	// Venues
+	VenueCreate       Permission = "venue:create"
+	VenueListOwn      Permission = "venue:list_own"
+	VenueEdit         Permission = "venue:edit" // details, photos, history, pending revision
+	VenueResubmit     Permission = "venue:resubmit"
+	VenueManage       Permission = "venue:manage" // lifecycle and staff
+	VenueViewBookings Permission = "venue:view_bookings"
+	VenueViewRevenue  Permission = "venue:view_revenue"
+	VenueBlockSlots   Permission = "venue:block_slots" // venue comes from the body; checked in the handler
+	PhotoEdit         Permission = "photo:edit"
+	StatsOwnVenues    Permission = "stats:own_venues"
+
+	// Bookings
+	BookingCreate  Permission = "booking:create"
+	BookingListOwn Permission = "booking:list_own"
+	BookingPay     Permission = "booking:pay"
+	BookingCancel  Permission = "booking:cancel"
+	BookingBlock   Permission = "booking:block"
+	BookingCheckIn Permission = "booking:check_in"
+
+	// Teams
+	TeamCreate      Permission = "team:create"
+	TeamListOwn     Permission = "team:list_own"
+	TeamRespond     Permission = "team:respond" // accept or reject an invite
+	TeamInvite      Permission = "team:invite"
+	TeamViewMembers Permission = "team:view_members"
+	TeamChatPost    Permission = "team:chat_post"
+	TeamChatRead    Permission = "team:chat_read"
+
+	// Profile
+	ProfileView    Permission = "profile:view"
+	ProfileEdit    Permission = "profile:edit"
+	ProfileAvatar  Permission = "profile:avatar"
+	SessionEndAll  Permission = "session:end_all" // log out of all devices
+	EmailResend    Permission = "email:resend"    // resend the verification link
+	PasswordChange Permission = "password:change"
+	PhoneVerify    Permission = "phone:verify"
+
+	// Reviews
+	ReviewCreate      Permission = "review:create"
+	ReviewEdit        Permission = "review:edit"
+	ReviewDelete      Permission = "review:delete"
+	ReviewReport      Permission = "review:report"
+	ReviewPhotoAdd    Permission = "review:photo_add"
+	ReviewPhotoDelete Permission = "review:photo_delete"
+	ReviewReply       Permission = "review:reply"
+
+	// Notifications
+	NotificationList Permission = "notification:list"
+	NotificationRead Permission = "notification:read"
+
+	// Venue staff (the staff member's side)
+	StaffListOwn Permission = "staff:list_own"
+	StaffRespond Permission = "staff:respond"
+
+	// Leagues (organiser and captain checks live in the league service)
+	LeagueCreate         Permission = "league:create"
+	SeasonCreate         Permission = "season:create"
+	SeasonAddTeam        Permission = "season:add_team"
+	FixtureCreate        Permission = "fixture:create"
+	FixtureSubmitResult  Permission = "fixture:submit_result"
+	FixtureConfirmResult Permission = "fixture:confirm_result"
+
+	// Players
+	PlayerSearch Permission = "player:search"
+)
+
+// Venue staff permission names. These are the only definition: the venue
+// package grants and stores them under these names (venue.StaffPermissions).
+const (
+	StaffViewBookings = "view_bookings"
+	StaffBlockSlots   = "block_slots"
+	StaffCheckIn      = "check_in"
+	StaffEditListing  = "edit_listing"
+	StaffViewRevenue  = "view_revenue"
+)
+
+// Rules is the policy for every permission
+var Rules = map[Permission]Rule{
+	VenueListAll:   {Roles: adminOnly},
+	VenueDecide:    {Roles: adminOnly},
+	BookingListAll: {Roles: adminOnly},
+	ReviewModerate: {Roles: adminOnly},
+	RevisionDecide: {Roles: adminOnly},
+	TaxonomyManage: {Roles: adminOnly},
+	StatsPlatform:  {Roles: adminOnly},
+	UserManage:     {Roles: adminOnly},
+
+	VenueCreate:       {Roles: anyUser, VerifiedEmail: true},
+	VenueListOwn:      {Roles: anyUser},
+	VenueEdit:         {Roles: anyUser, Resource: ResourceVenue, Scope: ScopeVenueStaff, StaffPermission: StaffEditListing, AdminOverride: true},
+	VenueResubmit:     {Roles: anyUser, Resource: ResourceVenue, Scope: ScopeVenueOwner},
+	VenueManage:       {Roles: anyUser, Resource: ResourceVenue, Scope: ScopeVenueOwner, AdminOverride: true},
+	VenueViewBookings: {Roles: anyUser, Resource: ResourceVenue, Scope: ScopeVenueStaff, StaffPermission: StaffViewBookings, AdminOverride: true},
+	VenueViewRevenue:  {Roles: anyUser, Resource: ResourceVenue, Scope: ScopeVenueStaff, StaffPermission: StaffViewRevenue, AdminOverride: true},
+	VenueBlockSlots:   {Roles: anyUser, Resource: ResourceVenue, Scope: ScopeVenueStaff, StaffPermission: StaffBlockSlots, AdminOverride: true},
+	PhotoEdit:         {Roles: anyUser, Resource: ResourcePhoto, Scope: ScopeVenueStaff, StaffPermission: StaffEditListing, AdminOverride: true},
+	StatsOwnVenues:    {Roles: ownerAdmin},
+
+	BookingCreate:  {Roles: anyUser, VerifiedEmail: true},
+	BookingListOwn: {Roles: anyUser},
+	BookingPay:     {Roles: anyUser, Resource: ResourceBooking, Scope: ScopeOwn},
+	BookingCancel:  {Roles: anyUser, Resource: ResourceBooking, Scope: ScopeOwn},
+	BookingBlock:   {Roles: anyUser},
+	BookingCheckIn: {Roles: anyUser, Resource: ResourceBooking, Scope: ScopeVenueStaff, StaffPermission: StaffCheckIn, AdminOverride: true},
+
+	TeamCreate:      {Roles: anyUser},
+	TeamListOwn:     {Roles: anyUser},
+	TeamRespond:     {Roles: anyUser},
+	TeamInvite:      {Roles: anyUser, Resource: ResourceTeam, Scope: ScopeTeamMember},
+	TeamViewMembers: {Roles: anyUser, Resource: ResourceTeam, Scope: ScopeTeamMember},
+	TeamChatPost:    {Roles: anyUser, Resource: ResourceTeam, Scope: ScopeTeamMember},
+	TeamChatRead:    {Roles: anyUser, Resource: ResourceTeam, Scope: ScopeTeamMember},
+
+	ProfileView:    {Roles: anyUser},
+	ProfileEdit:    {Roles: anyUser},
+	ProfileAvatar:  {Roles: anyUser},
+	SessionEndAll:  {Roles: anyUser},
+	EmailResend:    {Roles: anyUser},
+	PasswordChange: {Roles: anyUser},
+	PhoneVerify:    {Roles: anyUser},
+
+	ReviewCreate:      {Roles: anyUser},
+	ReviewEdit:        {Roles: anyUser, Resource: ResourceReview, Scope: ScopeOwn},
+	ReviewDelete:      {Roles: anyUser, Resource: ResourceReview, Scope: ScopeOwn},
+	ReviewReport:      {Roles: anyUser},
+	ReviewPhotoAdd:    {Roles: anyUser, Resource: ResourceReview, Scope: ScopeOwn},
+	ReviewPhotoDelete: {Roles: anyUser, Resource: ResourceReview, Scope: ScopeOwn, AdminOverride: true},
+	ReviewReply:       {Roles: anyUser, Resource: ResourceReview, Scope: ScopeVenueStaff, StaffPermission: StaffEditListing, AdminOverride: true},
+
+	NotificationList: {Roles: anyUser},
+	NotificationRead: {Roles: anyUser, Resource: ResourceNotification, Scope: ScopeOwn},
+
+	StaffListOwn: {Roles: anyUser},
+	StaffRespond: {Roles: anyUser},
+
+	LeagueCreate:         {Roles: anyUser},
+	SeasonCreate:         {Roles: anyUser},
+	SeasonAddTeam:        {Roles: anyUser},
+	FixtureCreate:        {Roles: anyUser},
+	FixtureSubmitResult:  {Roles: anyUser},
+	FixtureConfirmResult: {Roles: anyUser},
+
+	PlayerSearch: {Roles: anyUser},
+}
@@ -0,0 +1,65 @@
+// rating/rating_handler.go
+package rating
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchPlayersHandler handles GET /api/v1/players?sport=&min_rating=&max_rating=&q=
+func SearchPlayersHandler(c *gin.Context) {
+	filter := PlayerSearchFilter{
+		Sport: c.Query("sport"),
+		Name:  c.Query("q"),
+	}
+
+	if v := c.Query("min_rating"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_rating must be a number"})
+			return
+		}
+		filter.MinRating = &n
+	}
+	if v := c.Query("max_rating"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "max_rating must be a number"})
+			return
+		}
+		filter.MaxRating = &n
+	}
+	if v := c.Query("limit"); v != "" {
+		filter.Limit, _ = strconv.Atoi(v)
+	}
+
+	players, err := FindPlayers(filter)
+	if err != nil {
+		if errors.Is(err, ErrSportRequired) || errors.Is(err, ErrInvalidRatingRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not search players"})
+		return
+	}
+	c.JSON(http.StatusOK, players)
+}
+
+// GetRatingHistoryHandler handles GET /api/v1/players/:id/ratings/history?sport=
+func GetRatingHistoryHandler(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	history, err := GetRatingHistory(userID, c.Query("sport"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch rating history"})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,45 @@
+// rating/rating_model.go
+package rating
+
+import "time"
+
+// PlayerRating is a user's current Elo rating in one sport
+type PlayerRating struct {
+	UserID      int64     `json:"user_id"`
+	Sport       string    `json:"sport"`
+	Rating      int       `json:"rating"`
+	GamesPlayed int       `json:"games_played"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RatingChange is one entry in a user's rating history
+type RatingChange struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Sport     string    `json:"sport"`
+	FixtureID *int64    `json:"fixture_id,omitempty"`
+	OldRating int       `json:"old_rating"`
+	NewRating int       `json:"new_rating"`
+	Result    string    `json:"result"` // 'win', 'draw', 'loss'
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PlayerSearchResult is a public player card returned by player search
+type PlayerSearchResult struct {
+	UserID      int64  `json:"user_id"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	AvatarURL   string `json:"avatar_url"`
+	Sport       string `json:"sport"`
+	Rating      int    `json:"rating"`
+	GamesPlayed int    `json:"games_played"`
+}
+
+// PlayerSearchFilter holds the optional filters for player search
+type PlayerSearchFilter struct {
+	Sport     string
+	MinRating *int
+	MaxRating *int
+	Name      string
+	Limit     int
+}
@@ -0,0 +1,198 @@
+// rating/rating_repository.go
+package rating
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/JkD004/playarena-backend/db"
+)
+
+// FindRatingsByUserID fetches all sport ratings for a user
+func FindRatingsByUserID(userID int64) ([]PlayerRating, error) {
+	query := `
+		SELECT user_id, sport, rating, games_played, updated_at
+		FROM player_ratings
+		WHERE user_id = ?
+		ORDER BY sport
+	`
+	rows, err := db.DB.Query(query, userID)
+	if err != nil {
+		log.Println("Error querying player ratings:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := make([]PlayerRating, 0)
+	for rows.Next() {
+		var r PlayerRating
+		if err := rows.Scan(&r.UserID, &r.Sport, &r.Rating, &r.GamesPlayed, &r.UpdatedAt); err != nil {
+			log.Println("Error scanning player rating:", err)
+			continue
+		}
+		ratings = append(ratings, r)
+	}
+	return ratings, nil
+}
+
+// FindRatingsByUserIDs fetches ratings for many users at once, keyed by user ID
+func FindRatingsByUserIDs(userIDs []int64) (map[int64][]PlayerRating, error) {
+	result := make(map[int64][]PlayerRating)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIDs)), ",")
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+
+	query := `
+		SELECT user_id, sport, rating, games_played, updated_at
+		FROM player_ratings
+		WHERE user_id IN (` + placeholders + `)
+		ORDER BY sport
+	`
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error querying ratings for users:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r PlayerRating
+		if err := rows.Scan(&r.UserID, &r.Sport, &r.Rating, &r.GamesPlayed, &r.UpdatedAt); err != nil {
+			continue
+		}
+		result[r.UserID] = append(result[r.UserID], r)
+	}
+	return result, nil
+}
+
+// getRatingForUpdate reads a rating inside a transaction, locking the row.
+// Players without a rating yet start at DefaultRating.
+func getRatingForUpdate(tx *sql.Tx, userID int64, sport string) (int, int, error) {
+	var rating, games int
+	query := `SELECT rating, games_played FROM player_ratings WHERE user_id = ? AND sport = ? FOR UPDATE`
+	err := tx.QueryRow(query, userID, sport).Scan(&rating, &games)
+	if err == sql.ErrNoRows {
+		return DefaultRating, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return rating, games, nil
+}
+
+// saveRating upserts a rating and appends a history entry
+func saveRating(tx *sql.Tx, userID int64, sport string, oldRating, newRating int, fixtureID *int64, result string) error {
+	upsert := `
+		INSERT INTO player_ratings (user_id, sport, rating, games_played)
+		VALUES (?, ?, ?, 1)
+		ON DUPLICATE KEY UPDATE rating = VALUES(rating), games_played = games_played + 1
+	`
+	if _, err := tx.Exec(upsert, userID, sport, newRating); err != nil {
+		log.Println("Error saving player rating:", err)
+		return err
+	}
+
+	var fixture sql.NullInt64
+	if fixtureID != nil {
+		fixture = sql.NullInt64{Int64: *fixtureID, Valid: true}
+	}
+
+	history := `
+		INSERT INTO player_rating_history (user_id, sport, fixture_id, old_rating, new_rating, result)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	if _, err := tx.Exec(history, userID, sport, fixture, oldRating, newRating, result); err != nil {
+		log.Println("Error saving rating history:", err)
+		return err
+	}
+	return nil
+}
+
+// FindRatingHistory fetches a user's rating changes, newest first.
+// An empty sport returns history for all sports.
+func FindRatingHistory(userID int64, sport string) ([]RatingChange, error) {
+	query := `
+		SELECT id, user_id, sport, fixture_id, old_rating, new_rating, result, created_at
+		FROM player_rating_history
+		WHERE user_id = ?
+	`
+	args := []interface{}{userID}
+	if sport != "" {
+		query += ` AND sport = ?`
+		args = append(args, sport)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT 100`
+
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error querying rating history:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]RatingChange, 0)
+	for rows.Next() {
+		var h RatingChange
+		var fixtureID sql.NullInt64
+		if err := rows.Scan(&h.ID, &h.UserID, &h.Sport, &fixtureID, &h.OldRating, &h.NewRating, &h.Result, &h.CreatedAt); err != nil {
+			log.Println("Error scanning rating history:", err)
+			continue
+		}
+		if fixtureID.Valid {
+			h.FixtureID = &fixtureID.Int64
+		}
+		history = append(history, h)
+	}
+	return history, nil
+}
+
+// SearchPlayers finds rated players in a sport, optionally within a rating band
+func SearchPlayers(f PlayerSearchFilter) ([]PlayerSearchResult, error) {
+	query := `
+		SELECT u.id, u.first_name, u.last_name, COALESCE(u.avatar_url, ''), pr.sport, pr.rating, pr.games_played
+		FROM player_ratings pr
+		JOIN users u ON pr.user_id = u.id
+		WHERE pr.sport = ?
+	`
+	args := []interface{}{f.Sport}
+
+	if f.MinRating != nil {
+		query += ` AND pr.rating >= ?`
+		args = append(args, *f.MinRating)
+	}
+	if f.MaxRating != nil {
+		query += ` AND pr.rating <= ?`
+		args = append(args, *f.MaxRating)
+	}
+	if f.Name != "" {
+		query += ` AND CONCAT(u.first_name, ' ', u.last_name) LIKE ?`
+		args = append(args, "%"+f.Name+"%")
+	}
+	query += ` ORDER BY pr.rating DESC LIMIT ?`
+	args = append(args, f.Limit)
+
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error searching players:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	players := make([]PlayerSearchResult, 0)
+	for rows.Next() {
+		var p PlayerSearchResult
+		if err := rows.Scan(&p.UserID, &p.FirstName, &p.LastName, &p.AvatarURL, &p.Sport, &p.Rating, &p.GamesPlayed); err != nil {
+			log.Println("Error scanning player:", err)
+			continue
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
@@ -0,0 +1,164 @@
+// rating/rating_service.go
+package rating
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	// DefaultRating is the starting rating for a player new to a sport
+	DefaultRating = 1200
+
+	// Players in their first ProvisionalGames games move faster
+	ProvisionalGames = 10
+	kFactorNew       = 40
+	kFactorSettled   = 24
+)
+
+// Errors from validating a player search
+var (
+	ErrSportRequired      = errors.New("sport is required")
+	ErrInvalidRatingRange = errors.New("min_rating cannot be greater than max_rating")
+)
+
+// NormalizeSport makes "Football", " football " etc. share one rating
+func NormalizeSport(sport string) string {
+	return strings.ToLower(strings.TrimSpace(sport))
+}
+
+// ExpectedScore is the Elo win probability of a side rated `rating` against `opponent`
+func ExpectedScore(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+// RecordMatch updates ratings for every player on both sides of a team match.
+// Each side is rated by its players' average; every player on a side
+// receives the same adjustment (scaled by their own K-factor).
+// Players listed on both sides are left out: they can't win and lose the
+// same match. If that leaves a side empty, nobody is rated.
+// It runs inside the caller's transaction, so the ratings are saved exactly
+// when (and only if) the result itself is.
+func RecordMatch(tx *sql.Tx, sport string, homePlayers, awayPlayers []int64, homeScore, awayScore int, fixtureID *int64) error {
+	sport = NormalizeSport(sport)
+	if sport == "" {
+		return errors.New("sport is required")
+	}
+	if len(homePlayers) == 0 || len(awayPlayers) == 0 {
+		return errors.New("both sides need at least one player")
+	}
+	homePlayers, awayPlayers = withoutShared(homePlayers, awayPlayers)
+	if len(homePlayers) == 0 || len(awayPlayers) == 0 {
+		return nil
+	}
+
+	type current struct{ rating, games int }
+	load := func(ids []int64) (map[int64]current, float64, error) {
+		out := make(map[int64]current, len(ids))
+		total := 0
+		for _, id := range ids {
+			r, g, err := getRatingForUpdate(tx, id, sport)
+			if err != nil {
+				return nil, 0, err
+			}
+			out[id] = current{r, g}
+			total += r
+		}
+		return out, float64(total) / float64(len(ids)), nil
+	}
+
+	home, homeAvg, err := load(homePlayers)
+	if err != nil {
+		return err
+	}
+	away, awayAvg, err := load(awayPlayers)
+	if err != nil {
+		return err
+	}
+
+	homeActual, homeResult, awayResult := 0.5, "draw", "draw"
+	if homeScore > awayScore {
+		homeActual, homeResult, awayResult = 1, "win", "loss"
+	} else if homeScore < awayScore {
+		homeActual, homeResult, awayResult = 0, "loss", "win"
+	}
+	homeExpected := ExpectedScore(homeAvg, awayAvg)
+
+	apply := func(players map[int64]current, actual, expected float64, result string) error {
+		for id, cur := range players {
+			k := float64(kFactorSettled)
+			if cur.games < ProvisionalGames {
+				k = kFactorNew
+			}
+			newRating := cur.rating + int(math.Round(k*(actual-expected)))
+			if err := saveRating(tx, id, sport, cur.rating, newRating, fixtureID, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := apply(home, homeActual, homeExpected, homeResult); err != nil {
+		return err
+	}
+	return apply(away, 1-homeActual, 1-homeExpected, awayResult)
+}
+
+// withoutShared drops every player who appears in both lists
+func withoutShared(home, away []int64) ([]int64, []int64) {
+	inHome := make(map[int64]bool, len(home))
+	for _, id := range home {
+		inHome[id] = true
+	}
+	shared := make(map[int64]bool)
+	for _, id := range away {
+		if inHome[id] {
+			shared[id] = true
+		}
+	}
+	if len(shared) == 0 {
+		return home, away
+	}
+	keep := func(ids []int64) []int64 {
+		out := make([]int64, 0, len(ids))
+		for _, id := range ids {
+			if !shared[id] {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+	return keep(home), keep(away)
+}
+
+// GetUserRatings is the service-layer function
+func GetUserRatings(userID int64) ([]PlayerRating, error) {
+	return FindRatingsByUserID(userID)
+}
+
+// GetRatingsForUsers is the service-layer function
+func GetRatingsForUsers(userIDs []int64) (map[int64][]PlayerRating, error) {
+	return FindRatingsByUserIDs(userIDs)
+}
+
+// GetRatingHistory is the service-layer function
+func GetRatingHistory(userID int64, sport string) ([]RatingChange, error) {
+	return FindRatingHistory(userID, NormalizeSport(sport))
+}
+
+// FindPlayers validates the filter and searches for rated players
+func FindPlayers(f PlayerSearchFilter) ([]PlayerSearchResult, error) {
+	f.Sport = NormalizeSport(f.Sport)
+	if f.Sport == "" {
+		return nil, ErrSportRequired
+	}
+	if f.MinRating != nil && f.MaxRating != nil && *f.MinRating > *f.MaxRating {
+		return nil, ErrInvalidRatingRange
+	}
+	if f.Limit <= 0 || f.Limit > 100 {
+		f.Limit = 50
+	}
+	return SearchPlayers(f)
+}
@@ -0,0 +1,57 @@
+// sms/sms.go
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Sender delivers text messages (one-time codes)
+type Sender interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// devMode reports whether APP_ENV=development, which allows the log driver
+// to stand in for a real one
+func devMode() bool {
+	return os.Getenv("APP_ENV") == "development"
+}
+
+// NewFromEnv builds the sender selected by SMS_DRIVER:
+//
+//	twilio - sends through Twilio using TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN
+//	         and TWILIO_FROM
+//	log    - writes messages to the log instead of sending them; bodies
+//	         (which hold one-time codes) are only written when
+//	         APP_ENV=development
+//	memory - keeps messages in memory (tests)
+//
+// Without SMS_DRIVER, log is used when APP_ENV=development and startup fails
+// otherwise.
+func NewFromEnv() (Sender, error) {
+	driver := os.Getenv("SMS_DRIVER")
+	if driver == "" {
+		if !devMode() {
+			return nil, errors.New("SMS_DRIVER is not set (use APP_ENV=development to log texts instead)")
+		}
+		driver = "log"
+	}
+
+	switch driver {
+	case "twilio":
+		return NewTwilio(os.Getenv("TWILIO_ACCOUNT_SID"), os.Getenv("TWILIO_AUTH_TOKEN"), os.Getenv("TWILIO_FROM"))
+	case "log":
+		if !devMode() {
+			log.Println("⚠️  SMS_DRIVER=log outside development: texts are not sent and bodies are not logged")
+		}
+		return NewLog(devMode()), nil
+	case "memory":
+		log.Println("⚠️  Using in-memory SMS sender, texts are kept in memory and never sent")
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown SMS_DRIVER %q", driver)
+	}
+}
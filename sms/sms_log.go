@@ -0,0 +1,27 @@
+// sms/sms_log.go
+package sms
+
+import (
+	"context"
+	"log"
+)
+
+// Log writes messages to the log instead of sending them. Useful in
+// development, where codes can be copied from the output. Bodies carry
+// those codes, so they are only written when showBody is set.
+type Log struct {
+	showBody bool
+}
+
+func NewLog(showBody bool) *Log {
+	return &Log{showBody: showBody}
+}
+
+func (l *Log) Send(ctx context.Context, to, body string) error {
+	if !l.showBody {
+		log.Printf("📱 SMS to %s (body not logged)", to)
+		return nil
+	}
+	log.Printf("📱 SMS to %s: %s", to, body)
+	return nil
+}
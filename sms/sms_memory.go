@@ -0,0 +1,37 @@
+// sms/sms_memory.go
+package sms
+
+import (
+	"context"
+	"sync"
+)
+
+// Message is a text kept by Memory
+type Message struct {
+	To   string
+	Body string
+}
+
+// Memory keeps sent messages in memory. Useful for tests.
+type Memory struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Send(ctx context.Context, to, body string) error {
+	m.mu.Lock()
+	m.sent = append(m.sent, Message{To: to, Body: body})
+	m.mu.Unlock()
+	return nil
+}
+
+// Sent returns a copy of every message sent so far
+func (m *Memory) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
@@ -0,0 +1,59 @@
+// sms/sms_twilio.go
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned when a provider is missing required settings
+var ErrNotConfigured = errors.New("sms provider is not configured")
+
+// Twilio sends messages through Twilio's REST API
+type Twilio struct {
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+func NewTwilio(accountSID, authToken, from string) (*Twilio, error) {
+	if accountSID == "" || authToken == "" || from == "" {
+		return nil, ErrNotConfigured
+	}
+	return &Twilio{
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (t *Twilio) Send(ctx context.Context, to, body string) error {
+	endpoint := "https://api.twilio.com/2010-04-01/Accounts/" + url.PathEscape(t.accountSID) + "/Messages.json"
+	form := url.Values{"To": {to}, "From": {t.from}, "Body": {body}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.accountSID, t.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("twilio: %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// storage/storage.go
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// Object is a stored file: Key identifies it for deletion, URL is public
+type Object struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// Storage is the media backend used for venue photos, avatars and review photos
+type Storage interface {
+	// Put stores the content under the given folder and returns its key and public URL
+	Put(ctx context.Context, folder string, r io.Reader, contentType string) (*Object, error)
+
+	// Delete removes a stored object. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// PublicURL returns the public URL for a key
+	PublicURL(key string) string
+}
+
+// ErrNotConfigured is returned when a backend is missing required settings
+var ErrNotConfigured = errors.New("storage backend is not configured")
+
+// NewFromEnv builds the backend selected by STORAGE_DRIVER:
+//
+//	cloudinary - uses CLOUDINARY_URL
+//	local      - writes under LOCAL_STORAGE_DIR (default ./uploads), served at LOCAL_STORAGE_URL (default /uploads)
+//	memory     - keeps files in memory (tests / throwaway dev)
+//
+// When STORAGE_DRIVER is empty, cloudinary is used if CLOUDINARY_URL is set
+// and local storage otherwise.
+func NewFromEnv() (Storage, error) {
+	driver := os.Getenv("STORAGE_DRIVER")
+	if driver == "" {
+		driver = "local"
+		if os.Getenv("CLOUDINARY_URL") != "" {
+			driver = "cloudinary"
+		}
+	}
+
+	switch driver {
+	case "cloudinary":
+		return NewCloudinary(os.Getenv("CLOUDINARY_URL"))
+	case "local":
+		dir := os.Getenv("LOCAL_STORAGE_DIR")
+		if dir == "" {
+			dir = "./uploads"
+		}
+		baseURL := os.Getenv("LOCAL_STORAGE_URL")
+		if baseURL == "" {
+			baseURL = "/uploads"
+		}
+		return NewLocal(dir, baseURL)
+	case "memory":
+		log.Println("⚠️  Using in-memory storage, uploads will be lost on restart")
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", driver)
+	}
+}
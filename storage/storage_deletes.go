@@ -0,0 +1,164 @@
+// storage/storage_deletes.go
+package storage
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/JkD004/playarena-backend/db"
+)
+
+const (
+	// deleteTimeout bounds one background deletion, retries included
+	deleteTimeout = time.Minute
+
+	// Failed deletes are retried with a doubling delay, up to a day apart
+	pendingDeleteBackoff    = time.Minute
+	pendingDeleteMaxBackoff = 24 * time.Hour
+	pendingDeleteBatch      = 100
+)
+
+// DeleteLater removes files in the background so a slow or retrying provider
+// doesn't hold up the request. Each key is recorded in pending_deletes before
+// anything is attempted and only cleared once the delete succeeds, so a
+// restart or a long outage doesn't orphan the file: RetryPendingDeletes
+// picks up whatever is left.
+func DeleteLater(s Storage, keys ...string) {
+	ids := make(map[string]int64, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		// Leave the background attempt time to finish before a sweep retries it
+		id, err := createPendingDelete(key, time.Now().Add(deleteTimeout))
+		if err != nil {
+			log.Printf("Error recording pending delete of %s: %v", key, err)
+		}
+		ids[key] = id
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), deleteTimeout)
+		defer cancel()
+		for key, id := range ids {
+			err := s.Delete(ctx, key)
+			if err != nil {
+				log.Printf("Error deleting stored file %s: %v", key, err)
+			}
+			if id != 0 {
+				finishPendingDelete(id, 0, err)
+			}
+		}
+	}()
+}
+
+// RetryPendingDeletes retries failed deletes every interval until ctx is done.
+// Deleting is idempotent, so several instances may sweep at once.
+func RetryPendingDeletes(ctx context.Context, s Storage, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sweepPendingDeletes(ctx, s)
+		}
+	}
+}
+
+func sweepPendingDeletes(ctx context.Context, s Storage) {
+	due, err := findDuePendingDeletes(time.Now(), pendingDeleteBatch)
+	if err != nil {
+		return
+	}
+	for _, p := range due {
+		deleteCtx, cancel := context.WithTimeout(ctx, deleteTimeout)
+		err := s.Delete(deleteCtx, p.key)
+		cancel()
+		if err != nil {
+			log.Printf("Retry %d of deleting stored file %s failed: %v", p.attempts+1, p.key, err)
+		}
+		finishPendingDelete(p.id, p.attempts, err)
+	}
+}
+
+// finishPendingDelete clears a pending delete that succeeded, or schedules
+// the next attempt of one that failed
+func finishPendingDelete(id int64, attempts int, deleteErr error) {
+	if deleteErr == nil {
+		if err := removePendingDelete(id); err != nil {
+			log.Printf("Error clearing pending delete %d: %v", id, err)
+		}
+		return
+	}
+
+	backoff := pendingDeleteBackoff
+	for i := 0; i < attempts && backoff < pendingDeleteMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > pendingDeleteMaxBackoff {
+		backoff = pendingDeleteMaxBackoff
+	}
+	if err := reschedulePendingDelete(id, deleteErr.Error(), time.Now().Add(backoff)); err != nil {
+		log.Printf("Error rescheduling pending delete %d: %v", id, err)
+	}
+}
+
+type pendingDelete struct {
+	id       int64
+	key      string
+	attempts int
+}
+
+func createPendingDelete(key string, nextAttempt time.Time) (int64, error) {
+	result, err := db.DB.Exec(`INSERT INTO pending_deletes (storage_key, next_attempt_at) VALUES (?, ?)`, key, nextAttempt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func findDuePendingDeletes(now time.Time, limit int) ([]pendingDelete, error) {
+	query := `
+		SELECT id, storage_key, attempts FROM pending_deletes
+		WHERE next_attempt_at <= ?
+		ORDER BY next_attempt_at
+		LIMIT ?
+	`
+	rows, err := db.DB.Query(query, now, limit)
+	if err != nil {
+		log.Println("Error finding pending deletes:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []pendingDelete
+	for rows.Next() {
+		var p pendingDelete
+		if err := rows.Scan(&p.id, &p.key, &p.attempts); err != nil {
+			log.Println("Error scanning pending delete:", err)
+			continue
+		}
+		due = append(due, p)
+	}
+	return due, rows.Err()
+}
+
+func reschedulePendingDelete(id int64, lastError string, nextAttempt time.Time) error {
+	if len(lastError) > 500 {
+		lastError = lastError[:500]
+	}
+	query := `UPDATE pending_deletes SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?`
+	_, err := db.DB.Exec(query, lastError, nextAttempt, id)
+	return err
+}
+
+func removePendingDelete(id int64) error {
+	_, err := db.DB.Exec(`DELETE FROM pending_deletes WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,107 @@
+// storage/storage_local.go
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores media on the local filesystem. The directory should be
+// served at BaseURL (see Local.Dir / Local.BaseURL in main.go).
+type Local struct {
+	Dir     string
+	BaseURL string
+}
+
+// NewLocal creates the upload directory if needed
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Local{Dir: dir, BaseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (l *Local) Put(ctx context.Context, folder string, r io.Reader, contentType string) (*Object, error) {
+	key, err := newKey(folder, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	full, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(full)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(full)
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(full)
+		return nil, err
+	}
+
+	return &Object{Key: key, URL: l.PublicURL(key)}, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	full, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) PublicURL(key string) string {
+	return l.BaseURL + "/" + key
+}
+
+// path maps a key to a file inside Dir, refusing keys that escape it
+func (l *Local) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", errors.New("invalid storage key")
+	}
+	return filepath.Join(l.Dir, filepath.FromSlash(clean)), nil
+}
+
+// newKey builds a random, collision-free key such as "playarena_venues/3f9a...c1.jpg"
+func newKey(folder, contentType string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	ext := ""
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+		ext = exts[0]
+		if contentType == "image/jpeg" {
+			ext = ".jpg"
+		}
+	}
+
+	name := hex.EncodeToString(buf) + ext
+	if folder == "" {
+		return name, nil
+	}
+	return strings.Trim(folder, "/") + "/" + name, nil
+}
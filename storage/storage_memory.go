@@ -0,0 +1,54 @@
+// storage/storage_memory.go
+package storage
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Memory keeps objects in a map. Useful for tests and local experiments.
+type Memory struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+func NewMemory() *Memory {
+	return &Memory{objects: make(map[string][]byte)}
+}
+
+func (m *Memory) Put(ctx context.Context, folder string, r io.Reader, contentType string) (*Object, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	key, err := newKey(folder, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.objects[key] = data
+	m.mu.Unlock()
+
+	return &Object{Key: key, URL: m.PublicURL(key)}, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.objects, key)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) PublicURL(key string) string {
+	return "memory://" + key
+}
+
+// Get returns a stored object's content (test helper)
+func (m *Memory) Get(key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.objects[key]
+	return data, ok
+}
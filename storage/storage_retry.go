@@ -0,0 +1,47 @@
+// storage/storage_retry.go
+package storage
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Retrying wraps a backend so deletes are retried with exponential backoff
+// when the provider is briefly unavailable. Uploads are not retried: the
+// request body can only be read once.
+type Retrying struct {
+	Storage
+	Attempts int
+	Backoff  time.Duration // delay before the first retry, doubled each time
+}
+
+// WithRetries wraps s with retrying deletes
+func WithRetries(s Storage, attempts int, backoff time.Duration) *Retrying {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &Retrying{Storage: s, Attempts: attempts, Backoff: backoff}
+}
+
+func (r *Retrying) Delete(ctx context.Context, key string) error {
+	delay := r.Backoff
+	var err error
+	for attempt := 1; attempt <= r.Attempts; attempt++ {
+		if err = r.Storage.Delete(ctx, key); err == nil {
+			return nil
+		}
+		if attempt == r.Attempts {
+			break
+		}
+
+		log.Printf("Delete of %s failed (attempt %d/%d): %v", key, attempt, r.Attempts, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return err
+}
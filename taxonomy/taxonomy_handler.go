@@ -0,0 +1,129 @@
+// taxonomy/taxonomy_handler.go
+package taxonomy
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSportsHandler handles GET /api/v1/sports (active sports, for filters and forms)
+func GetSportsHandler(c *gin.Context) {
+	sports, err := GetSports(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch sports"})
+		return
+	}
+	c.JSON(http.StatusOK, sports)
+}
+
+// GetAmenitiesHandler handles GET /api/v1/amenities
+func GetAmenitiesHandler(c *gin.Context) {
+	amenities, err := GetAmenities(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch amenities"})
+		return
+	}
+	c.JSON(http.StatusOK, amenities)
+}
+
+// -------------------------------------------------------
+// ADMIN
+// -------------------------------------------------------
+
+// AdminGetSportsHandler handles GET /api/v1/admin/sports (including inactive)
+func AdminGetSportsHandler(c *gin.Context) {
+	sports, err := GetSports(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch sports"})
+		return
+	}
+	c.JSON(http.StatusOK, sports)
+}
+
+// CreateSportHandler handles POST /api/v1/admin/sports
+func CreateSportHandler(c *gin.Context) {
+	var req CreateSportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	sport, err := AddSport(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, sport)
+}
+
+// UpdateSportHandler handles PATCH /api/v1/admin/sports/:id
+func UpdateSportHandler(c *gin.Context) {
+	sportID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sport ID"})
+		return
+	}
+
+	var req UpdateSportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	sport, err := EditSport(sportID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sport)
+}
+
+// AdminGetAmenitiesHandler handles GET /api/v1/admin/amenities (including inactive)
+func AdminGetAmenitiesHandler(c *gin.Context) {
+	amenities, err := GetAmenities(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch amenities"})
+		return
+	}
+	c.JSON(http.StatusOK, amenities)
+}
+
+// CreateAmenityHandler handles POST /api/v1/admin/amenities
+func CreateAmenityHandler(c *gin.Context) {
+	var req CreateAmenityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	amenity, err := AddAmenity(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, amenity)
+}
+
+// UpdateAmenityHandler handles PATCH /api/v1/admin/amenities/:id
+func UpdateAmenityHandler(c *gin.Context) {
+	amenityID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid amenity ID"})
+		return
+	}
+
+	var req UpdateAmenityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	amenity, err := EditAmenity(amenityID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, amenity)
+}
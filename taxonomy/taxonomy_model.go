@@ -0,0 +1,54 @@
+// taxonomy/taxonomy_model.go
+package taxonomy
+
+import "time"
+
+// Sport is an entry in the managed sports list. Venues, search filters and
+// stats all refer to sports by slug (e.g. "football", "table_tennis").
+type Sport struct {
+	ID         int64     `json:"id"`
+	Slug       string    `json:"slug"`
+	Name       string    `json:"name"`
+	Aliases    []string  `json:"aliases"` // other spellings that resolve to this sport, e.g. "soccer"
+	Active     bool      `json:"active"`
+	VenueCount int       `json:"venue_count"` // approved venues offering the sport
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Amenity is an entry in the managed amenities list (parking, showers, ...)
+type Amenity struct {
+	ID         int64     `json:"id"`
+	Slug       string    `json:"slug"`
+	Name       string    `json:"name"`
+	Active     bool      `json:"active"`
+	VenueCount int       `json:"venue_count"` // approved venues with the amenity
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateSportRequest is the body of POST /admin/sports.
+// The slug is derived from the name when omitted.
+type CreateSportRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Slug    string   `json:"slug"`
+	Aliases []string `json:"aliases"`
+}
+
+// UpdateSportRequest is the body of PATCH /admin/sports/:id.
+// Omitted fields are left unchanged; aliases, when sent, replace the list.
+type UpdateSportRequest struct {
+	Name    *string  `json:"name"`
+	Aliases []string `json:"aliases"`
+	Active  *bool    `json:"active"`
+}
+
+// CreateAmenityRequest is the body of POST /admin/amenities
+type CreateAmenityRequest struct {
+	Name string `json:"name" binding:"required"`
+	Slug string `json:"slug"`
+}
+
+// UpdateAmenityRequest is the body of PATCH /admin/amenities/:id
+type UpdateAmenityRequest struct {
+	Name   *string `json:"name"`
+	Active *bool   `json:"active"`
+}
@@ -0,0 +1,180 @@
+// taxonomy/taxonomy_repository.go
+package taxonomy
+
+import (
+	"log"
+
+	"github.com/JkD004/playarena-backend/db"
+)
+
+// FindSports lists sports with their aliases, optionally including inactive ones
+func FindSports(includeInactive bool) ([]Sport, error) {
+	query := `
+		SELECT s.id, s.slug, s.name, s.active, s.created_at,
+		       (SELECT COUNT(*) FROM venue_sports vs JOIN venues v ON v.id = vs.venue_id
+		        WHERE vs.sport_id = s.id AND v.status = 'approved')
+		FROM sports s`
+	if !includeInactive {
+		query += ` WHERE s.active = TRUE`
+	}
+	query += ` ORDER BY s.name`
+
+	rows, err := db.DB.Query(query)
+	if err != nil {
+		log.Println("Error querying sports:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	sports := make([]Sport, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var s Sport
+		if err := rows.Scan(&s.ID, &s.Slug, &s.Name, &s.Active, &s.CreatedAt, &s.VenueCount); err != nil {
+			log.Println("Error scanning sport:", err)
+			continue
+		}
+		s.Aliases = make([]string, 0)
+		index[s.ID] = len(sports)
+		sports = append(sports, s)
+	}
+
+	aliasRows, err := db.DB.Query(`SELECT sport_id, alias FROM sport_aliases ORDER BY alias`)
+	if err != nil {
+		log.Println("Error querying sport aliases:", err)
+		return nil, err
+	}
+	defer aliasRows.Close()
+
+	for aliasRows.Next() {
+		var sportID int64
+		var alias string
+		if err := aliasRows.Scan(&sportID, &alias); err != nil {
+			continue
+		}
+		if i, ok := index[sportID]; ok {
+			sports[i].Aliases = append(sports[i].Aliases, alias)
+		}
+	}
+	return sports, nil
+}
+
+// CreateSport saves a sport and its aliases
+func CreateSport(s *Sport) error {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO sports (slug, name) VALUES (?, ?)`, s.Slug, s.Name)
+	if err != nil {
+		log.Println("Error creating sport:", err)
+		return err
+	}
+	s.ID, _ = result.LastInsertId()
+	s.Active = true
+
+	for _, alias := range s.Aliases {
+		if _, err := tx.Exec(`INSERT INTO sport_aliases (alias, sport_id) VALUES (?, ?)`, alias, s.ID); err != nil {
+			log.Println("Error creating sport alias:", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateSport saves the name/active flag and, when aliases is non-nil,
+// replaces the alias list
+func UpdateSport(s *Sport, aliases []string) error {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE sports SET name = ?, active = ? WHERE id = ?`, s.Name, s.Active, s.ID); err != nil {
+		log.Println("Error updating sport:", err)
+		return err
+	}
+
+	// Keep the denormalized venue column in step with the sport's name
+	if _, err := tx.Exec(`
+		UPDATE venues v
+		JOIN venue_sports vs ON vs.venue_id = v.id AND vs.is_primary = TRUE
+		SET v.sport_category = ?
+		WHERE vs.sport_id = ?`, s.Name, s.ID); err != nil {
+		log.Println("Error renaming venue sport:", err)
+		return err
+	}
+
+	if aliases != nil {
+		if _, err := tx.Exec(`DELETE FROM sport_aliases WHERE sport_id = ?`, s.ID); err != nil {
+			log.Println("Error clearing sport aliases:", err)
+			return err
+		}
+		for _, alias := range aliases {
+			if _, err := tx.Exec(`INSERT INTO sport_aliases (alias, sport_id) VALUES (?, ?)`, alias, s.ID); err != nil {
+				log.Println("Error creating sport alias:", err)
+				return err
+			}
+		}
+		s.Aliases = aliases
+	}
+	return tx.Commit()
+}
+
+// FindAmenities lists amenities, optionally including inactive ones
+func FindAmenities(includeInactive bool) ([]Amenity, error) {
+	query := `
+		SELECT a.id, a.slug, a.name, a.active, a.created_at,
+		       (SELECT COUNT(*) FROM venue_amenities va JOIN venues v ON v.id = va.venue_id
+		        WHERE va.amenity_id = a.id AND v.status = 'approved')
+		FROM amenities a`
+	if !includeInactive {
+		query += ` WHERE a.active = TRUE`
+	}
+	query += ` ORDER BY a.name`
+
+	rows, err := db.DB.Query(query)
+	if err != nil {
+		log.Println("Error querying amenities:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	amenities := make([]Amenity, 0)
+	for rows.Next() {
+		var a Amenity
+		if err := rows.Scan(&a.ID, &a.Slug, &a.Name, &a.Active, &a.CreatedAt, &a.VenueCount); err != nil {
+			log.Println("Error scanning amenity:", err)
+			continue
+		}
+		amenities = append(amenities, a)
+	}
+	return amenities, nil
+}
+
+// CreateAmenity saves a new amenity
+func CreateAmenity(a *Amenity) error {
+	result, err := db.DB.Exec(`INSERT INTO amenities (slug, name) VALUES (?, ?)`, a.Slug, a.Name)
+	if err != nil {
+		log.Println("Error creating amenity:", err)
+		return err
+	}
+	a.ID, _ = result.LastInsertId()
+	a.Active = true
+	return nil
+}
+
+// UpdateAmenity saves an amenity's name and active flag
+func UpdateAmenity(a *Amenity) error {
+	_, err := db.DB.Exec(`UPDATE amenities SET name = ?, active = ? WHERE id = ?`, a.Name, a.Active, a.ID)
+	if err != nil {
+		log.Println("Error updating amenity:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+// taxonomy/taxonomy_service.go
+package taxonomy
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Slugify turns free text into the slug form used by the reference tables:
+// "Changing Rooms" -> "changing_rooms", " Table-Tennis " -> "table_tennis"
+func Slugify(s string) string {
+	var b strings.Builder
+	pendingSep := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingSep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			pendingSep = false
+		} else {
+			pendingSep = true
+		}
+	}
+	return b.String()
+}
+
+// GetSports lists the sports (active only unless includeInactive)
+func GetSports(includeInactive bool) ([]Sport, error) {
+	return FindSports(includeInactive)
+}
+
+// GetAmenities lists the amenities (active only unless includeInactive)
+func GetAmenities(includeInactive bool) ([]Amenity, error) {
+	return FindAmenities(includeInactive)
+}
+
+// ResolveSports maps free-text sport names to active sports, matching slug,
+// name or alias ("Soccer" -> football). Duplicates are dropped; the order
+// is kept so the first entry can be treated as the primary sport.
+func ResolveSports(names []string) ([]Sport, error) {
+	sports, err := FindSports(false)
+	if err != nil {
+		return nil, errors.New("could not load sports")
+	}
+
+	lookup := make(map[string]Sport)
+	for _, s := range sports {
+		lookup[s.Slug] = s
+		lookup[Slugify(s.Name)] = s
+		for _, a := range s.Aliases {
+			lookup[a] = s
+		}
+	}
+
+	seen := make(map[int64]bool)
+	out := make([]Sport, 0, len(names))
+	for _, name := range names {
+		s, ok := lookup[Slugify(name)]
+		if !ok {
+			return nil, errors.New("unknown sport: " + name)
+		}
+		if !seen[s.ID] {
+			seen[s.ID] = true
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// ResolveAmenities maps free-text amenity names to active amenities
+func ResolveAmenities(names []string) ([]Amenity, error) {
+	amenities, err := FindAmenities(false)
+	if err != nil {
+		return nil, errors.New("could not load amenities")
+	}
+
+	lookup := make(map[string]Amenity)
+	for _, a := range amenities {
+		lookup[a.Slug] = a
+		lookup[Slugify(a.Name)] = a
+	}
+
+	seen := make(map[int64]bool)
+	out := make([]Amenity, 0, len(names))
+	for _, name := range names {
+		a, ok := lookup[Slugify(name)]
+		if !ok {
+			return nil, errors.New("unknown amenity: " + name)
+		}
+		if !seen[a.ID] {
+			seen[a.ID] = true
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// normalizeAliases slugifies and de-duplicates aliases, dropping the sport's own slug
+func normalizeAliases(aliases []string, slug string) []string {
+	seen := map[string]bool{slug: true}
+	out := make([]string, 0, len(aliases))
+	for _, a := range aliases {
+		a = Slugify(a)
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		out = append(out, a)
+	}
+	return out
+}
+
+// sportSlugTaken reports whether a slug (or alias) is already used by another sport
+func sportSlugTaken(slug string, exceptID int64, all []Sport) bool {
+	for _, s := range all {
+		if s.ID == exceptID {
+			continue
+		}
+		if s.Slug == slug {
+			return true
+		}
+		for _, a := range s.Aliases {
+			if a == slug {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AddSport creates a sport (admin)
+func AddSport(req *CreateSportRequest) (*Sport, error) {
+	name := strings.TrimSpace(req.Name)
+	slug := Slugify(req.Slug)
+	if slug == "" {
+		slug = Slugify(name)
+	}
+	if name == "" || slug == "" {
+		return nil, errors.New("name is required")
+	}
+
+	all, err := FindSports(true)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	aliases := normalizeAliases(req.Aliases, slug)
+	for _, key := range append([]string{slug}, aliases...) {
+		if sportSlugTaken(key, 0, all) {
+			return nil, errors.New("'" + key + "' is already used by another sport")
+		}
+	}
+
+	s := &Sport{Slug: slug, Name: name, Aliases: aliases}
+	if err := CreateSport(s); err != nil {
+		return nil, errors.New("could not create sport")
+	}
+	return s, nil
+}
+
+// EditSport renames, (de)activates or re-aliases a sport (admin).
+// The slug never changes so existing links and filters keep working.
+func EditSport(sportID int64, req *UpdateSportRequest) (*Sport, error) {
+	all, err := FindSports(true)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	var s *Sport
+	for i := range all {
+		if all[i].ID == sportID {
+			s = &all[i]
+		}
+	}
+	if s == nil {
+		return nil, errors.New("sport not found")
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		s.Name = name
+	}
+	if req.Active != nil {
+		s.Active = *req.Active
+	}
+
+	var aliases []string
+	if req.Aliases != nil {
+		aliases = normalizeAliases(req.Aliases, s.Slug)
+		for _, a := range aliases {
+			if sportSlugTaken(a, s.ID, all) {
+				return nil, errors.New("'" + a + "' is already used by another sport")
+			}
+		}
+	}
+
+	if err := UpdateSport(s, aliases); err != nil {
+		return nil, errors.New("could not update sport")
+	}
+	return s, nil
+}
+
+// AddAmenity creates an amenity (admin)
+func AddAmenity(req *CreateAmenityRequest) (*Amenity, error) {
+	name := strings.TrimSpace(req.Name)
+	slug := Slugify(req.Slug)
+	if slug == "" {
+		slug = Slugify(name)
+	}
+	if name == "" || slug == "" {
+		return nil, errors.New("name is required")
+	}
+
+	all, err := FindAmenities(true)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	for _, a := range all {
+		if a.Slug == slug {
+			return nil, errors.New("an amenity with this slug already exists")
+		}
+	}
+
+	a := &Amenity{Slug: slug, Name: name}
+	if err := CreateAmenity(a); err != nil {
+		return nil, errors.New("could not create amenity")
+	}
+	return a, nil
+}
+
+// EditAmenity renames or (de)activates an amenity (admin)
+func EditAmenity(amenityID int64, req *UpdateAmenityRequest) (*Amenity, error) {
+	all, err := FindAmenities(true)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	var a *Amenity
+	for i := range all {
+		if all[i].ID == amenityID {
+			a = &all[i]
+		}
+	}
+	if a == nil {
+		return nil, errors.New("amenity not found")
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		a.Name = name
+	}
+	if req.Active != nil {
+		a.Active = *req.Active
+	}
+
+	if err := UpdateAmenity(a); err != nil {
+		return nil, errors.New("could not update amenity")
+	}
+	return a, nil
+}
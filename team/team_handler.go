@@ -2,6 +2,7 @@
 package team
 
 import (
+	"errors"
 	"net/http"
 	"github.com/gin-gonic/gin"
 	"strconv"
@@ -153,9 +154,9 @@ func PostMessageHandler(c *gin.Context) {
 		return
 	}
 
-	err = SendTeamMessage(teamID, userID, req.Message)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
+	// Membership is also checked by the route policy (policy.TeamChatPost)
+	if _, err := PostChatMessage(userID, teamID, req.Message); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -170,9 +171,16 @@ func GetTeamMessagesHandler(c *gin.Context) {
 		return
 	}
 
-	messages, err := GetTeamChat(teamID)
+	userID := c.MustGet("userID").(int64)
+
+	// Membership is also checked by the route policy (policy.TeamChatRead)
+	messages, err := GetTeamMessages(userID, teamID)
+	if errors.Is(err, ErrNotTeamMember) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch chat"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch messages"})
 		return
 	}
 
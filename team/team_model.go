@@ -4,6 +4,8 @@ package team
 import (
 	"time"
 	"database/sql"
+
+	"github.com/JkD004/playarena-backend/rating"
 )
 
 
@@ -43,6 +45,7 @@ type TeamMemberDetails struct {
 	LastName  string `json:"last_name"`
 	Email     string `json:"email"`
 	Status    string `json:"status"`
+	Ratings   []rating.PlayerRating `json:"ratings"`
 }
 
 
@@ -303,4 +303,16 @@ func GetMessagesByTeamID(teamID int64) ([]ChatMessage, error) {
 		messages = make([]ChatMessage, 0)
 	}
 	return messages, nil
-}
\ No newline at end of file
+}
+
+// FindTeamByID fetches a single team by its ID
+func FindTeamByID(teamID int64) (*Team, error) {
+	query := "SELECT id, name, owner_id, created_at FROM teams WHERE id = ?"
+
+	var t Team
+	err := db.DB.QueryRow(query, teamID).Scan(&t.ID, &t.Name, &t.OwnerID, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
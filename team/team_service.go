@@ -6,8 +6,12 @@ import (
 	"log"
 	"github.com/JkD004/playarena-backend/user"
 	"github.com/JkD004/playarena-backend/notification"
+	"github.com/JkD004/playarena-backend/rating"
 )
 
+// ErrNotTeamMember is returned when a non-member tries to use a team's members or chat
+var ErrNotTeamMember = errors.New("you are not a member of this team")
+
 // CreateNewTeam handles the logic for creating a new team
 func CreateNewTeam(req *CreateTeamRequest, ownerID int64) (*Team, error) {
 	if req.Name == "" {
@@ -112,10 +116,31 @@ func GetTeamMembers(userID, teamID int64) ([]TeamMemberDetails, error) {
 		return nil, errors.New("database error checking membership")
 	}
 	if !isMember {
-		return nil, errors.New("you are not a member of this team")
+		return nil, ErrNotTeamMember
+	}
+
+	members, err := FindMembersByTeamID(teamID)
+	if err != nil {
+		return nil, err
 	}
 
-	return FindMembersByTeamID(teamID)
+	// Attach each member's sport ratings for skill-matching
+	userIDs := make([]int64, len(members))
+	for i, m := range members {
+		userIDs[i] = m.UserID
+	}
+	ratings, err := rating.GetRatingsForUsers(userIDs)
+	if err != nil {
+		log.Println("Error fetching member ratings:", err)
+		ratings = nil
+	}
+	for i := range members {
+		members[i].Ratings = ratings[members[i].UserID]
+		if members[i].Ratings == nil {
+			members[i].Ratings = make([]rating.PlayerRating, 0)
+		}
+	}
+	return members, nil
 }
 
 // PostChatMessage handles logic for sending a new message
@@ -125,7 +150,7 @@ func PostChatMessage(userID, teamID int64, message string) (*ChatMessage, error)
 		return nil, errors.New("database error checking membership")
 	}
 	if !isMember {
-		return nil, errors.New("you are not a member of this team")
+		return nil, ErrNotTeamMember
 	}
 
 	if message == "" {
@@ -142,7 +167,7 @@ func GetTeamMessages(userID, teamID int64) ([]ChatMessageDetails, error) {
 		return nil, errors.New("database error checking membership")
 	}
 	if !isMember {
-		return nil, errors.New("you are not a member of this team")
+		return nil, ErrNotTeamMember
 	}
 
 	return FindChatMessagesByTeamID(teamID)
@@ -169,4 +194,24 @@ func SendTeamMessage(teamID, userID int64, content string) error {
 
 func GetTeamChat(teamID int64) ([]ChatMessage, error) {
 	return GetMessagesByTeamID(teamID)
-}
\ No newline at end of file
+}
+// GetTeamByID is the service-layer function
+func GetTeamByID(teamID int64) (*Team, error) {
+	return FindTeamByID(teamID)
+}
+
+// GetJoinedMemberIDs returns the user IDs of everyone who has joined a team
+func GetJoinedMemberIDs(teamID int64) ([]int64, error) {
+	members, err := FindMembersByTeamID(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(members))
+	for _, m := range members {
+		if m.Status == "joined" {
+			ids = append(ids, m.UserID)
+		}
+	}
+	return ids, nil
+}
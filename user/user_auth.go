@@ -0,0 +1,149 @@
+// user/user_auth.go
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultAuthCacheTTL is how long a looked-up AuthState is trusted
+const defaultAuthCacheTTL = 30 * time.Second
+
+var (
+	ErrAccountSuspended = errors.New("account is suspended")
+	ErrTokenRevoked     = errors.New("token has been revoked")
+	ErrUserNotFound     = errors.New("user not found")
+)
+
+type cachedAuthState struct {
+	state   AuthState
+	expires time.Time
+}
+
+var (
+	authCacheMu sync.Mutex
+	authCache   = map[int64]cachedAuthState{}
+)
+
+// envDuration reads a duration such as "30s" or "15m" from the environment
+func envDuration(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return def
+	}
+	return d
+}
+
+// authCacheTTL reads AUTH_CACHE_TTL ("0" disables the cache)
+func authCacheTTL() time.Duration {
+	return envDuration("AUTH_CACHE_TTL", defaultAuthCacheTTL)
+}
+
+// LookupAuthState returns a user's current role and account state, from the
+// cache when a recent enough copy exists
+func LookupAuthState(userID int64) (*AuthState, error) {
+	now := time.Now()
+	authCacheMu.Lock()
+	cached, ok := authCache[userID]
+	authCacheMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		s := cached.state
+		return &s, nil
+	}
+
+	s, err := FindAuthState(userID)
+	if err != nil {
+		return nil, err
+	}
+	if ttl := authCacheTTL(); ttl > 0 {
+		authCacheMu.Lock()
+		authCache[userID] = cachedAuthState{state: *s, expires: now.Add(ttl)}
+		authCacheMu.Unlock()
+	}
+	return s, nil
+}
+
+// InvalidateAuthState drops the cached state of a user so the next request
+// sees their new role or status straight away
+func InvalidateAuthState(userID int64) {
+	authCacheMu.Lock()
+	delete(authCache, userID)
+	authCacheMu.Unlock()
+}
+
+// ResolveAuth checks a parsed token against the user's live account state and
+// returns that state; its Role, not the token's, is the one to trust.
+func ResolveAuth(claims *Claims) (*AuthState, error) {
+	s, err := LookupAuthState(claims.UserID)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.Status == StatusSuspended {
+		return nil, ErrAccountSuspended
+	}
+	if claims.TokenVersion != s.TokenVersion {
+		return nil, ErrTokenRevoked
+	}
+	return s, nil
+}
+
+// SetUserStatus suspends or reactivates an account. Suspending revokes every
+// access and refresh token the user holds.
+func SetUserStatus(actorID, userID int64, status string) error {
+	if status != StatusActive && status != StatusSuspended {
+		return errors.New("status must be 'active' or 'suspended'")
+	}
+	if actorID == userID {
+		return errors.New("you cannot change your own account status")
+	}
+	if _, err := FindAuthState(userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	if _, err := UpdateUserStatusInDB(userID, status); err != nil {
+		return errors.New("failed to update account status")
+	}
+	if status == StatusSuspended {
+		_ = RevokeUserRefreshTokens(nil, userID)
+	}
+	InvalidateAuthState(userID)
+	return nil
+}
+
+// ChangeUserRole sets a user's role and revokes their access tokens, so a
+// demoted user cannot keep using a token issued for the old role. Their next
+// refresh picks up the new role.
+func ChangeUserRole(actorID, userID int64, role string) error {
+	valid := false
+	for _, r := range Roles {
+		if role == r {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return errors.New("role must be 'player', 'owner' or 'admin'")
+	}
+	if actorID == userID {
+		return errors.New("you cannot change your own role")
+	}
+	if _, err := FindAuthState(userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	if _, err := SetUserRoleInDB(userID, role); err != nil {
+		return errors.New("failed to update role")
+	}
+	InvalidateAuthState(userID)
+	return nil
+}
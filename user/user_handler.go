@@ -1,19 +1,20 @@
 package user
 
 import (
-	"context" // <-- Add this
+	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/JkD004/playarena-backend/imaging"
+	"github.com/JkD004/playarena-backend/storage"
 	"github.com/gin-gonic/gin"
-	"github.com/cloudinary/cloudinary-go/v2" // <-- Add this
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader" // <-- Add this
 )
 
-// Global Cloudinary instance for User package
-var cld *cloudinary.Cloudinary
+// Global media storage for User package (avatars)
+var store storage.Storage
 
-func SetCloudinary(cloudinaryClient *cloudinary.Cloudinary) {
-	cld = cloudinaryClient
+func SetStorage(s storage.Storage) {
+	store = s
 }
 
 func RegisterUserHandler(c *gin.Context) {
@@ -30,7 +31,40 @@ func RegisterUserHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "User registered successfully!"})
+	c.JSON(http.StatusCreated, gin.H{"message": "User registered successfully! Check your email to verify your address."})
+}
+
+// VerifyEmailHandler handles GET /api/v1/verify-email?token= (the emailed link)
+func VerifyEmailHandler(c *gin.Context) {
+	if err := VerifyEmail(c.Query("token")); err != nil {
+		if errors.Is(err, ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// ResendVerificationHandler handles POST /api/v1/verify-email/resend
+func ResendVerificationHandler(c *gin.Context) {
+	userID := c.MustGet("userID").(int64)
+
+	if err := ResendVerificationEmail(userID); err != nil {
+		switch {
+		case errors.Is(err, ErrEmailAlreadyVerified):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrResendTooSoon):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification email sent"})
 }
 
 type LoginRequest struct {
@@ -47,20 +81,83 @@ func LoginUserHandler(c *gin.Context) {
 		return
 	}
 
-	// 1. Get both token and role from the service
-	tokenString, userRole, err := LoginUser(req.Email, req.Password)
+	// 1. Start a session: a short-lived access token plus a refresh token
+	pair, err := LoginUser(req.Email, req.Password)
+	if errors.Is(err, ErrAccountSuspended) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 2. Send them both to the frontend
+	// 2. Send them to the frontend
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful!",
-		"token":   tokenString,
-		"role":    userRole, // <-- ADD THIS LINE
+		"message":       "Login successful!",
+		"token":         pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+		"expires_in":    pair.ExpiresIn,
+		"role":          pair.Role,
 	})
 }
+
+// RefreshTokenHandler handles POST /api/v1/token/refresh
+func RefreshTokenHandler(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token is required"})
+		return
+	}
+
+	pair, err := RefreshTokens(req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrInvalidRefreshToken), errors.Is(err, ErrRefreshTokenReuse):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, pair)
+}
+
+// LogoutHandler handles POST /api/v1/logout (ends the current session)
+func LogoutHandler(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token is required"})
+		return
+	}
+
+	if err := Logout(req.RefreshToken); err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// LogoutAllHandler handles POST /api/v1/logout/all (every device)
+func LogoutAllHandler(c *gin.Context) {
+	userID := c.MustGet("userID").(int64)
+
+	if err := LogoutAll(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all devices"})
+}
+
 // GetProfileHandler handles fetching the logged-in user's profile
 // user/user_handler.go
 // ... (keep existing functions)
@@ -89,6 +186,10 @@ func UpdateProfileHandler(c *gin.Context) {
 	}
 
 	err := UpdateUserProfile(userID, &userUpdates)
+	if errors.Is(err, ErrPhoneInUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
 		return
@@ -114,24 +215,231 @@ func UploadProfilePicHandler(c *gin.Context) {
 	}
 	defer src.Close()
 
-	// Upload to Cloudinary folder 'playarena_users'
-	ctx := context.Background()
-	params := uploader.UploadParams{Folder: "playarena_users"}
+	// Upload to storage folder 'playarena_users' and replace the old avatar
+	imageURL, err := UpdateAvatar(userID, src)
+	if err != nil {
+		var invalid *imaging.ValidationError
+		if errors.As(err, &invalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	result, err := cld.Upload.Upload(ctx, src, params)
+	c.JSON(http.StatusOK, gin.H{"message": "Avatar updated", "url": imageURL})
+}
+// UpdateUserStatusHandler handles PATCH /api/v1/admin/users/:id/status
+func UpdateUserStatusHandler(c *gin.Context) {
+	adminID := c.MustGet("userID").(int64)
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
 
-	imageURL := result.SecureURL
+	var req UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
 
-	// Save to DB
-	err = UpdateUserAvatar(userID, imageURL)
+	if err := SetUserStatus(adminID, userID, req.Status); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account status updated", "status": req.Status})
+}
+
+// UpdateUserRoleHandler handles PATCH /api/v1/admin/users/:id/role
+func UpdateUserRoleHandler(c *gin.Context) {
+	adminID := c.MustGet("userID").(int64)
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save avatar URL"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Avatar updated", "url": imageURL})
-}
\ No newline at end of file
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	if err := ChangeUserRole(adminID, userID, req.Role); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated", "role": req.Role})
+}
+
+// ForgotPasswordHandler handles POST /api/v1/password/forgot. The answer is
+// the same whether or not the account exists.
+func ForgotPasswordHandler(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+		return
+	}
+
+	RequestPasswordReset(req.Email)
+	c.JSON(http.StatusOK, gin.H{"message": "If an account exists for that email, a reset link has been sent."})
+}
+
+// ResetPasswordHandler handles POST /api/v1/password/reset
+func ResetPasswordHandler(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	if err := ResetPassword(req.Token, req.NewPassword, req.ConfirmPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated. Please log in with your new password."})
+}
+
+// ChangePasswordHandler handles POST /api/v1/profile/password
+func ChangePasswordHandler(c *gin.Context) {
+	userID := c.MustGet("userID").(int64)
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	pair, err := ChangePassword(userID, req.CurrentPassword, req.NewPassword, req.ConfirmPassword)
+	if err != nil {
+		if errors.Is(err, ErrWrongPassword) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Other devices are signed out; this one continues with the new tokens
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Password changed",
+		"token":         pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+		"expires_in":    pair.ExpiresIn,
+		"role":          pair.Role,
+	})
+}
+
+// SendPhoneVerificationHandler handles POST /api/v1/phone/verify/send
+func SendPhoneVerificationHandler(c *gin.Context) {
+	userID := c.MustGet("userID").(int64)
+
+	if err := SendPhoneVerification(userID, c.ClientIP()); err != nil {
+		switch {
+		case errors.Is(err, ErrOTPRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrPhoneVerified):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrNoPhoneOnProfile), errors.Is(err, ErrInvalidPhone):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification code sent"})
+}
+
+// VerifyPhoneHandler handles POST /api/v1/phone/verify
+func VerifyPhoneHandler(c *gin.Context) {
+	userID := c.MustGet("userID").(int64)
+
+	var req VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code is required"})
+		return
+	}
+
+	if err := VerifyPhone(userID, req.Code, c.ClientIP()); err != nil {
+		switch {
+		case errors.Is(err, ErrOTPRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrPhoneVerified):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Phone number verified"})
+}
+
+// SendLoginOTPHandler handles POST /api/v1/login/otp/send. The answer is the
+// same whether or not the number is registered.
+func SendLoginOTPHandler(c *gin.Context) {
+	var req SendOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Phone number is required"})
+		return
+	}
+
+	if err := RequestLoginOTP(req.Phone, c.ClientIP()); err != nil {
+		switch {
+		case errors.Is(err, ErrOTPRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrInvalidPhone):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the number is registered, a code has been sent."})
+}
+
+// OTPLoginHandler handles POST /api/v1/login/otp
+func OTPLoginHandler(c *gin.Context) {
+	var req OTPLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	pair, err := LoginWithOTP(req.Phone, req.Code, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrOTPRateLimited):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrAccountSuspended):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrInvalidOTP):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Login successful!",
+		"token":         pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+		"expires_in":    pair.ExpiresIn,
+		"role":          pair.Role,
+	})
+}
@@ -1,19 +1,150 @@
 package user
 
-import "time"
+import (
+	"time"
+
+	"github.com/JkD004/playarena-backend/rating"
+)
 
 type User struct {
 	ID              int64     `json:"id"`
 	FirstName       string    `json:"first_name"`
 	LastName        string    `json:"last_name"`
 	Phone           string    `json:"phone,omitempty"`
+	PhoneVerified   bool      `json:"phone_verified"`
 	DOB             string    `json:"dob,omitempty"`
 	Address         string    `json:"address,omitempty"`
 	Email           string    `json:"email"`
+	EmailVerified   bool      `json:"email_verified"`
 	Password        string    `json:"password,omitempty"`
 	ConfirmPassword string    `json:"confirm_password,omitempty"`
 	PasswordHash    string    `json:"-"`
 	Role            string    `json:"role,omitempty"`
+	Status          string    `json:"status,omitempty"` // active, suspended
+	TokenVersion    int       `json:"-"`
 	CreatedAt       time.Time `json:"created_at"`
 	AvatarURL 		string 	  `json:"avatar_url"`
-}
\ No newline at end of file
+	Ratings         []rating.PlayerRating `json:"ratings,omitempty"` // Per-sport skill ratings (profile only)
+}
+
+// Account states
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+)
+
+// Roles a user can hold
+var Roles = []string{"player", "owner", "admin"}
+
+// AuthState is what the auth middleware checks on every request
+type AuthState struct {
+	UserID        int64
+	Email         string
+	Role          string
+	Status        string
+	TokenVersion  int
+	EmailVerified bool
+}
+
+// UpdateUserStatusRequest is the body of PATCH /admin/users/:id/status
+type UpdateUserStatusRequest struct {
+	Status string `json:"status" binding:"required"` // active, suspended
+}
+
+// UpdateUserRoleRequest is the body of PATCH /admin/users/:id/role
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// TokenPair is what login and refresh hand out
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // access token lifetime in seconds
+	Role         string `json:"role"`
+}
+
+// RefreshToken is a stored refresh token (the token itself is never stored)
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	FamilyID  string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+// RefreshRequest is the body of POST /token/refresh and POST /logout
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// EmailVerification is an outstanding email verification token
+type EmailVerification struct {
+	ID        int64
+	UserID    int64
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// PasswordReset is an emailed password reset token
+type PasswordReset struct {
+	ID        int64
+	UserID    int64
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+// ForgotPasswordRequest is the body of POST /password/forgot
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// ResetPasswordRequest is the body of POST /password/reset
+type ResetPasswordRequest struct {
+	Token           string `json:"token" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
+// ChangePasswordRequest is the body of POST /profile/password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
+// One-time code purposes
+const (
+	OTPVerifyPhone = "verify"
+	OTPLogin       = "login"
+)
+
+// PhoneOTP is a one-time code sent by SMS
+type PhoneOTP struct {
+	ID        int64
+	Phone     string
+	Purpose   string
+	UserID    int64 // 0 when no account has the number
+	CodeHash  string
+	Attempts  int
+	RequestIP string
+	ExpiresAt time.Time
+}
+
+// SendOTPRequest is the body of POST /login/otp/send
+type SendOTPRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// OTPLoginRequest is the body of POST /login/otp
+type OTPLoginRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+// VerifyPhoneRequest is the body of POST /phone/verify
+type VerifyPhoneRequest struct {
+	Code string `json:"code" binding:"required"`
+}
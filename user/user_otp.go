@@ -0,0 +1,305 @@
+// user/user_otp.go
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JkD004/playarena-backend/sms"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	otpLength      = 6
+	otpTTL         = 5 * time.Minute
+	otpMaxAttempts = 5 // wrong guesses allowed per code
+
+	otpResendCooldown = time.Minute      // between two codes to one number
+	otpPhoneWindow    = 15 * time.Minute // per-number limit window
+	otpPhoneLimit     = 3                // codes per number per window
+	otpIPWindow       = time.Hour        // per-IP limit window
+	otpIPLimit        = 10               // codes per IP address per window
+
+	otpFailureWindow     = time.Hour // window for the wrong-code limits
+	otpPhoneFailureLimit = 10        // wrong codes per number per window
+	otpIPFailureLimit    = 20        // wrong codes per IP address per window
+)
+
+var (
+	ErrInvalidPhone     = errors.New("invalid phone number")
+	ErrInvalidOTP       = errors.New("invalid or expired code")
+	ErrOTPRateLimited   = errors.New("too many codes requested; please try again later")
+	ErrNoPhoneOnProfile = errors.New("add a phone number to your profile first")
+	ErrPhoneVerified    = errors.New("phone number is already verified")
+	ErrPhoneInUse       = errors.New("phone number already in use")
+)
+
+// Global SMS sender for User package (one-time codes)
+var texts sms.Sender
+
+func SetSMS(s sms.Sender) {
+	texts = s
+}
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+
+// normalizePhone drops the separators people type ("+91 98765-43210")
+func normalizePhone(phone string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+}
+
+// newOTP returns a random numeric code
+func newOTP() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < otpLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", otpLength, n), nil
+}
+
+// checkOTPRateLimits applies the per-number and per-IP limits
+func checkOTPRateLimits(phone, ip string) error {
+	now := time.Now()
+	sent, latest, err := CountPhoneOTPsSince(phone, now.Add(-otpPhoneWindow))
+	if err != nil {
+		return errors.New("database error")
+	}
+	if sent >= otpPhoneLimit || (sent > 0 && now.Sub(latest) < otpResendCooldown) {
+		return ErrOTPRateLimited
+	}
+	fromIP, err := CountIPOTPsSince(ip, now.Add(-otpIPWindow))
+	if err != nil {
+		return errors.New("database error")
+	}
+	if fromIP >= otpIPLimit {
+		return ErrOTPRateLimited
+	}
+	return nil
+}
+
+// issueOTP stores a new code for the number (replacing older ones) and
+// returns it. userID is 0 when no account has the number: the row still
+// counts towards the rate limits, but nothing is sent.
+func issueOTP(phone, purpose string, userID int64, ip string) (string, error) {
+	if err := checkOTPRateLimits(phone, ip); err != nil {
+		return "", err
+	}
+	code, err := newOTP()
+	if err != nil {
+		return "", errors.New("could not generate code")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.New("could not generate code")
+	}
+
+	if err := ExpirePhoneOTPs(phone, purpose); err != nil {
+		return "", errors.New("database error")
+	}
+	err = CreatePhoneOTP(&PhoneOTP{
+		Phone:     phone,
+		Purpose:   purpose,
+		UserID:    userID,
+		CodeHash:  string(hash),
+		RequestIP: ip,
+		ExpiresAt: time.Now().Add(otpTTL),
+	})
+	if err != nil {
+		return "", errors.New("database error")
+	}
+	return code, nil
+}
+
+// sendOTP texts a code to the number
+func sendOTP(phone, code string) error {
+	if texts == nil {
+		log.Printf("No SMS sender configured; dropping code for %s", phone)
+		return errors.New("sms not configured")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	body := fmt.Sprintf("Your PlayArena code is %s. It expires in %d minutes. Don't share it with anyone.", code, int(otpTTL.Minutes()))
+	if err := texts.Send(ctx, phone, body); err != nil {
+		log.Printf("Error sending SMS to %s: %v", phone, err)
+		return err
+	}
+	return nil
+}
+
+// checkOTP redeems a code guessed from ip. Every guess is claimed against
+// the code's attempt limit before it is compared; once the limit is reached
+// the code is dead and a new one must be requested. Wrong guesses also count
+// towards limits per number and per IP address, across codes.
+func checkOTP(phone, purpose, code, ip string) (*PhoneOTP, error) {
+	byPhone, byIP, err := CountOTPFailuresSince(phone, ip, time.Now().Add(-otpFailureWindow))
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if byPhone >= otpPhoneFailureLimit || byIP >= otpIPFailureLimit {
+		return nil, ErrOTPRateLimited
+	}
+
+	o, err := redeemOTP(phone, purpose, code)
+	if errors.Is(err, ErrInvalidOTP) {
+		_ = RecordOTPFailure(phone, ip)
+	}
+	return o, err
+}
+
+func redeemOTP(phone, purpose, code string) (*PhoneOTP, error) {
+	o, err := FindActivePhoneOTP(phone, purpose)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidOTP
+	}
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if o.UserID == 0 {
+		return nil, ErrInvalidOTP
+	}
+	claimed, err := ClaimOTPAttempt(o.ID, otpMaxAttempts)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if !claimed {
+		return nil, ErrInvalidOTP
+	}
+	if bcrypt.CompareHashAndPassword([]byte(o.CodeHash), []byte(strings.TrimSpace(code))) != nil {
+		return nil, ErrInvalidOTP
+	}
+	consumed, err := ConsumePhoneOTP(o.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if !consumed {
+		return nil, ErrInvalidOTP
+	}
+	return o, nil
+}
+
+// SendPhoneVerification texts a code to the number on the user's profile
+func SendPhoneVerification(userID int64, ip string) error {
+	u, err := FindUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if u.Phone == "" {
+		return ErrNoPhoneOnProfile
+	}
+	if u.PhoneVerified {
+		return ErrPhoneVerified
+	}
+	phone := normalizePhone(u.Phone)
+	if !phonePattern.MatchString(phone) {
+		return ErrInvalidPhone
+	}
+
+	code, err := issueOTP(phone, OTPVerifyPhone, userID, ip)
+	if err != nil {
+		return err
+	}
+	if err := sendOTP(phone, code); err != nil {
+		return errors.New("failed to send code")
+	}
+	return nil
+}
+
+// VerifyPhone confirms the number on the user's profile with a code
+func VerifyPhone(userID int64, code, ip string) error {
+	u, err := FindUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if u.Phone == "" {
+		return ErrNoPhoneOnProfile
+	}
+	if u.PhoneVerified {
+		return ErrPhoneVerified
+	}
+
+	o, err := checkOTP(normalizePhone(u.Phone), OTPVerifyPhone, code, ip)
+	if err != nil {
+		return err
+	}
+	if o.UserID != userID {
+		return ErrInvalidOTP
+	}
+	if err := MarkPhoneVerified(userID, u.Phone); err != nil {
+		return errors.New("failed to verify phone")
+	}
+	return nil
+}
+
+// RequestLoginOTP texts a login code if the number is verified on an account.
+// The answer is the same either way, so it doesn't reveal who is registered;
+// only the rate limits can fail the request.
+func RequestLoginOTP(phone, ip string) error {
+	phone = normalizePhone(phone)
+	if !phonePattern.MatchString(phone) {
+		return ErrInvalidPhone
+	}
+
+	// Only a verified number can sign in; anyone can type a number into
+	// their profile
+	var userID int64
+	u, err := FindUserByPhone(phone)
+	if err == nil && u.PhoneVerified {
+		userID = u.ID
+	} else if err != nil && err != sql.ErrNoRows {
+		return errors.New("database error")
+	}
+
+	code, err := issueOTP(phone, OTPLogin, userID, ip)
+	if err != nil {
+		return err
+	}
+	if userID != 0 {
+		// Sent in the background so response times match unknown numbers
+		go func() { _ = sendOTP(phone, code) }()
+	}
+	return nil
+}
+
+// LoginWithOTP starts a session for the account that holds the number, as
+// long as it is still verified there.
+func LoginWithOTP(phone, code, ip string) (*TokenPair, error) {
+	phone = normalizePhone(phone)
+	if !phonePattern.MatchString(phone) {
+		return nil, ErrInvalidOTP
+	}
+
+	o, err := checkOTP(phone, OTPLogin, code, ip)
+	if err != nil {
+		return nil, err
+	}
+	// The number may have moved or been changed since the code was sent
+	holder, err := FindUserByPhone(phone)
+	if err != nil || holder.ID != o.UserID || !holder.PhoneVerified {
+		return nil, ErrInvalidOTP
+	}
+	s, err := FindAuthState(o.UserID)
+	if err != nil {
+		return nil, ErrInvalidOTP
+	}
+	if s.Status == StatusSuspended {
+		return nil, ErrAccountSuspended
+	}
+
+	familyID, err := newFamilyID()
+	if err != nil {
+		return nil, errors.New("could not generate token")
+	}
+	return issueTokens(nil, s, familyID)
+}
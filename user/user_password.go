@@ -0,0 +1,205 @@
+// user/user_password.go
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// passwordResetTTL is how long a reset link works
+	passwordResetTTL = time.Hour
+
+	// passwordResetCooldown is the minimum wait between two reset emails
+	passwordResetCooldown = time.Minute
+
+	// MinPasswordLength applies to passwords set by reset or change
+	MinPasswordLength = 8
+)
+
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired reset link")
+	ErrWrongPassword     = errors.New("current password is incorrect")
+)
+
+// passwordResetURL reads PASSWORD_RESET_URL, the frontend page that takes
+// the token and asks for a new password
+func passwordResetURL() string {
+	if u := os.Getenv("PASSWORD_RESET_URL"); u != "" {
+		return u
+	}
+	return appBaseURL() + "/reset-password"
+}
+
+// validateNewPassword checks a new password and returns its hash
+func validateNewPassword(password, confirm string) (string, error) {
+	if password != confirm {
+		return "", errors.New("passwords do not match")
+	}
+	if len(password) < MinPasswordLength {
+		return "", errors.New("password must be at least 8 characters")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.New("failed to hash password")
+	}
+	return string(hash), nil
+}
+
+// RequestPasswordReset emails a reset link if the address belongs to an
+// account. It returns straight away and does the work in the background, so
+// neither the response nor its timing reveals whether the account exists.
+func RequestPasswordReset(email string) {
+	email = strings.TrimSpace(email)
+	go func() {
+		if err := sendPasswordReset(email); err != nil {
+			log.Println("Error sending password reset:", err)
+		}
+	}()
+}
+
+func sendPasswordReset(email string) error {
+	u, err := FindUserByEmail(email)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if last, err := FindLatestPasswordResetAt(u.ID); err == nil && time.Since(last) < passwordResetCooldown {
+		return nil
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	// Only the newest link works
+	if err := ExpirePasswordResets(nil, u.ID); err != nil {
+		return err
+	}
+	if err := CreatePasswordReset(u.ID, hashToken(token), time.Now().Add(passwordResetTTL)); err != nil {
+		return err
+	}
+
+	link := passwordResetURL() + "?token=" + url.QueryEscape(token)
+	return sendMail(mailer.Message{
+		To:      u.Email,
+		Subject: "Reset your PlayArena password",
+		Body: "Hi " + u.FirstName + ",\n\n" +
+			"We received a request to reset your password. Open this link to choose a new one:\n\n" + link + "\n\n" +
+			"The link expires in 1 hour and can only be used once. If you didn't ask for this, you can ignore this email.\n",
+	})
+}
+
+// ResetPassword sets a new password using an emailed token and signs the
+// user out everywhere
+func ResetPassword(token, newPassword, confirm string) error {
+	hash, err := validateNewPassword(newPassword, confirm)
+	if err != nil {
+		return err
+	}
+
+	reset, err := FindPasswordReset(hashToken(token))
+	if err == sql.ErrNoRows {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return errors.New("database error")
+	}
+	if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return errors.New("database error")
+	}
+	defer tx.Rollback()
+
+	used, err := UsePasswordReset(tx, reset.ID)
+	if err != nil {
+		return errors.New("database error")
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+	if err := UpdatePasswordHash(tx, reset.UserID, hash); err != nil {
+		return errors.New("failed to update password")
+	}
+	// The link arrived by email, which proves the address
+	if err := MarkEmailVerified(tx, reset.UserID); err != nil {
+		return errors.New("failed to update password")
+	}
+	// Sessions end with the old password, or not at all
+	if err := RevokeUserRefreshTokens(tx, reset.UserID); err != nil {
+		return errors.New("failed to update password")
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.New("failed to update password")
+	}
+
+	InvalidateAuthState(reset.UserID)
+	return nil
+}
+
+// ChangePassword replaces the password of a signed-in user. Every other
+// session is revoked; the caller gets a fresh token pair to stay signed in.
+func ChangePassword(userID int64, current, newPassword, confirm string) (*TokenPair, error) {
+	stored, err := FindPasswordHash(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if bcrypt.CompareHashAndPassword([]byte(stored), []byte(current)) != nil {
+		return nil, ErrWrongPassword
+	}
+	if current == newPassword {
+		return nil, errors.New("new password must be different from the current one")
+	}
+	hash, err := validateNewPassword(newPassword, confirm)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return nil, errors.New("database error")
+	}
+	defer tx.Rollback()
+
+	if err := UpdatePasswordHash(tx, userID, hash); err != nil {
+		return nil, errors.New("failed to update password")
+	}
+	if err := ExpirePasswordResets(tx, userID); err != nil {
+		return nil, errors.New("failed to update password")
+	}
+	if err := RevokeUserRefreshTokens(tx, userID); err != nil {
+		return nil, errors.New("failed to update password")
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("failed to update password")
+	}
+
+	InvalidateAuthState(userID)
+
+	s, err := FindAuthState(userID)
+	if err != nil {
+		return nil, errors.New("password changed; please log in again")
+	}
+	familyID, err := newFamilyID()
+	if err != nil {
+		return nil, errors.New("password changed; please log in again")
+	}
+	return issueTokens(nil, s, familyID)
+}
@@ -4,7 +4,7 @@ package user
 import (
 	"database/sql" // We need this
 	"log"
-	//"time"
+	"time"
 
 	"github.com/JkD004/playarena-backend/db"
 )
@@ -12,21 +12,22 @@ import (
 // --- CreateUser (No changes) ---
 func CreateUser(user *User) error {
 	query := `INSERT INTO users (first_name, last_name, phone, dob, address, email, password_hash)
-			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+			  VALUES (?, ?, NULLIF(?, ''), ?, ?, ?, ?)` // no number is NULL, so it stays out of the unique index
 			  
-	_, err := db.DB.Exec(query, user.FirstName, user.LastName, user.Phone, user.DOB, user.Address, user.Email, user.PasswordHash)
+	result, err := db.DB.Exec(query, user.FirstName, user.LastName, user.Phone, user.DOB, user.Address, user.Email, user.PasswordHash)
 	
 	if err != nil {
 		log.Println("Error inserting user:", err)
 		return err
 	}
+	user.ID, _ = result.LastInsertId()
 	return nil
 }
 
 // --- FindUserByEmail (No changes) ---
 func FindUserByEmail(email string) (*User, error) {
 	var user User
-	query := "SELECT id, email, password_hash, first_name, last_name, role FROM users WHERE email = ?"
+	query := "SELECT id, email, password_hash, first_name, last_name, role, status, token_version FROM users WHERE email = ?"
 	err := db.DB.QueryRow(query, email).Scan(
 		&user.ID, 
 		&user.Email, 
@@ -34,6 +35,8 @@ func FindUserByEmail(email string) (*User, error) {
 		&user.FirstName, 
 		&user.LastName, 
 		&user.Role,
+		&user.Status,
+		&user.TokenVersion,
 	)
 	if err != nil {
 		log.Println("Error finding user by email:", err)
@@ -45,8 +48,8 @@ func FindUserByEmail(email string) (*User, error) {
 // --- FindUserByPhone (No changes) ---
 func FindUserByPhone(phone string) (*User, error) {
 	var user User
-	query := "SELECT id FROM users WHERE phone = ?"
-	err := db.DB.QueryRow(query, phone).Scan(&user.ID)
+	query := "SELECT id, phone_verified_at IS NOT NULL FROM users WHERE phone = ?"
+	err := db.DB.QueryRow(query, phone).Scan(&user.ID, &user.PhoneVerified)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +66,8 @@ func FindUserByID(userID int64) (*User, error) {
 	safeQuery := `
 		SELECT id, first_name, last_name, email, 
 		COALESCE(phone, ''), COALESCE(dob, ''), COALESCE(address, ''), 
-		role, created_at, COALESCE(avatar_url, '') 
+		role, created_at, COALESCE(avatar_url, ''), email_verified_at IS NOT NULL,
+		phone_verified_at IS NOT NULL
 		FROM users 
 		WHERE id = ?
 	`
@@ -72,6 +76,7 @@ func FindUserByID(userID int64) (*User, error) {
 		&user.ID, &user.FirstName, &user.LastName, &user.Email, 
 		&user.Phone, &user.DOB, &user.Address, 
 		&user.Role, &user.CreatedAt, &user.AvatarURL, // <-- Added AvatarURL
+		&user.EmailVerified, &user.PhoneVerified,
 	)
 	
 	if err != nil {
@@ -85,10 +90,12 @@ func FindUserByID(userID int64) (*User, error) {
 func UpdateUser(user *User) error {
 	query := `
 		UPDATE users 
-		SET first_name = ?, last_name = ?, phone = ?, dob = ?, address = ? 
+		SET phone_verified_at = IF(phone <=> NULLIF(?, ''), phone_verified_at, NULL), -- a new number needs verifying
+			first_name = ?, last_name = ?, phone = NULLIF(?, ''), dob = ?, address = ? 
 		WHERE id = ?
 	`
 	_, err := db.DB.Exec(query, 
+		user.Phone,
 		user.FirstName, user.LastName, user.Phone, 
 		user.DOB, user.Address, user.ID,
 	)
@@ -101,6 +108,8 @@ func UpdateUser(user *User) error {
 
 // UpdateUserRole updates a user's role in the database
 // It uses a transaction (tx) to ensure data integrity
+// Tokens stay valid: the promotion reaches them through the live role
+// lookup (call InvalidateAuthState after committing)
 func UpdateUserRole(tx *sql.Tx, userID int64, newRole string) error {
 	query := `UPDATE users SET role = ? WHERE id = ? AND role = 'player'`
 	
@@ -120,13 +129,440 @@ func UpdateUserRole(tx *sql.Tx, userID int64, newRole string) error {
 
 // user/user_repository.go
 
-// UpdateUserAvatar updates the avatar_url for a user
-func UpdateUserAvatar(userID int64, url string) error {
-	query := `UPDATE users SET avatar_url = ? WHERE id = ?`
-	_, err := db.DB.Exec(query, url, userID)
+// UpdateUserAvatar updates the avatar_url (and its storage key) for a user
+func UpdateUserAvatar(userID int64, url, key string) error {
+	query := `UPDATE users SET avatar_url = ?, avatar_key = ? WHERE id = ?`
+	_, err := db.DB.Exec(query, url, key, userID)
 	if err != nil {
 		log.Println("Error updating user avatar:", err)
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// FindUserAvatarKey returns the storage key of the user's current avatar ("" if none)
+func FindUserAvatarKey(userID int64) (string, error) {
+	var key string
+	query := `SELECT COALESCE(avatar_key, '') FROM users WHERE id = ?`
+	err := db.DB.QueryRow(query, userID).Scan(&key)
+	if err != nil {
+		log.Println("Error finding user avatar key:", err)
+		return "", err
+	}
+	return key, nil
+}
+
+// FindAuthState loads the role and account state of a user
+func FindAuthState(userID int64) (*AuthState, error) {
+	var s AuthState
+	query := `SELECT id, email, role, status, token_version, email_verified_at IS NOT NULL FROM users WHERE id = ?`
+	err := db.DB.QueryRow(query, userID).Scan(&s.UserID, &s.Email, &s.Role, &s.Status, &s.TokenVersion, &s.EmailVerified)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error finding auth state:", err)
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpdateUserStatusInDB sets the account status. Suspending also bumps the
+// token version so every issued token stops working.
+func UpdateUserStatusInDB(userID int64, status string) (bool, error) {
+	query := `
+		UPDATE users
+		SET status = ?, status_changed_at = NOW(),
+			token_version = token_version + IF(? = 'suspended', 1, 0)
+		WHERE id = ? AND status <> ?
+	`
+	result, err := db.DB.Exec(query, status, status, userID, status)
+	if err != nil {
+		log.Println("Error updating user status:", err)
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// SetUserRoleInDB changes a user's role and revokes their tokens
+func SetUserRoleInDB(userID int64, role string) (bool, error) {
+	query := `UPDATE users SET role = ?, token_version = token_version + 1 WHERE id = ? AND role <> ?`
+	result, err := db.DB.Exec(query, role, userID, role)
+	if err != nil {
+		log.Println("Error setting user role:", err)
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// BumpTokenVersion revokes every access token issued to a user
+func BumpTokenVersion(userID int64) error {
+	_, err := db.DB.Exec(`UPDATE users SET token_version = token_version + 1 WHERE id = ?`, userID)
+	if err != nil {
+		log.Println("Error bumping token version:", err)
+	}
+	return err
+}
+
+// CreateRefreshToken stores the hash of a new refresh token
+func CreateRefreshToken(tx *sql.Tx, userID int64, familyID, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at) VALUES (?, ?, ?, ?)`
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, userID, familyID, tokenHash, expiresAt)
+	} else {
+		_, err = db.DB.Exec(query, userID, familyID, tokenHash, expiresAt)
+	}
+	if err != nil {
+		log.Println("Error creating refresh token:", err)
+	}
+	return err
+}
+
+// FindRefreshToken looks up a refresh token by its hash
+func FindRefreshToken(tokenHash string) (*RefreshToken, error) {
+	var t RefreshToken
+	var usedAt, revokedAt sql.NullTime
+	query := `SELECT id, user_id, family_id, expires_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = ?`
+	err := db.DB.QueryRow(query, tokenHash).Scan(&t.ID, &t.UserID, &t.FamilyID, &t.ExpiresAt, &usedAt, &revokedAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error finding refresh token:", err)
+		}
+		return nil, err
+	}
+	if usedAt.Valid {
+		t.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	return &t, nil
+}
+
+// MarkRefreshTokenUsed rotates a token out. It reports false when the token
+// was already used or revoked, e.g. by a concurrent refresh.
+func MarkRefreshTokenUsed(tx *sql.Tx, id int64) (bool, error) {
+	query := `UPDATE refresh_tokens SET used_at = NOW() WHERE id = ? AND used_at IS NULL AND revoked_at IS NULL`
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		log.Println("Error marking refresh token used:", err)
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// RevokeRefreshFamily revokes every token of one login
+func RevokeRefreshFamily(familyID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = ? AND revoked_at IS NULL`
+	_, err := db.DB.Exec(query, familyID)
+	if err != nil {
+		log.Println("Error revoking refresh token family:", err)
+	}
+	return err
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of a user
+func RevokeUserRefreshTokens(tx *sql.Tx, userID int64) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = ? AND revoked_at IS NULL`
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, userID)
+	} else {
+		_, err = db.DB.Exec(query, userID)
+	}
+	if err != nil {
+		log.Println("Error revoking refresh tokens:", err)
+	}
+	return err
+}
+
+// CreateEmailVerification stores the hash of an emailed verification token
+func CreateEmailVerification(userID int64, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO email_verifications (user_id, token_hash, expires_at) VALUES (?, ?, ?)`
+	_, err := db.DB.Exec(query, userID, tokenHash, expiresAt)
+	if err != nil {
+		log.Println("Error creating email verification:", err)
+	}
+	return err
+}
+
+// FindEmailVerification looks up a verification token by its hash
+func FindEmailVerification(tokenHash string) (*EmailVerification, error) {
+	var v EmailVerification
+	query := `SELECT id, user_id, expires_at, created_at FROM email_verifications WHERE token_hash = ?`
+	err := db.DB.QueryRow(query, tokenHash).Scan(&v.ID, &v.UserID, &v.ExpiresAt, &v.CreatedAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error finding email verification:", err)
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// FindLatestEmailVerification returns the user's most recent token
+func FindLatestEmailVerification(userID int64) (*EmailVerification, error) {
+	var v EmailVerification
+	query := `
+		SELECT id, user_id, expires_at, created_at FROM email_verifications
+		WHERE user_id = ? ORDER BY created_at DESC, id DESC LIMIT 1
+	`
+	err := db.DB.QueryRow(query, userID).Scan(&v.ID, &v.UserID, &v.ExpiresAt, &v.CreatedAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error finding latest email verification:", err)
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// DeleteEmailVerifications removes every outstanding token of a user
+func DeleteEmailVerifications(tx *sql.Tx, userID int64) error {
+	query := `DELETE FROM email_verifications WHERE user_id = ?`
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, userID)
+	} else {
+		_, err = db.DB.Exec(query, userID)
+	}
+	if err != nil {
+		log.Println("Error deleting email verifications:", err)
+	}
+	return err
+}
+
+// MarkEmailVerified records that the user confirmed their email
+func MarkEmailVerified(tx *sql.Tx, userID int64) error {
+	query := `UPDATE users SET email_verified_at = NOW() WHERE id = ? AND email_verified_at IS NULL`
+	_, err := tx.Exec(query, userID)
+	if err != nil {
+		log.Println("Error marking email verified:", err)
+	}
+	return err
+}
+
+// FindPasswordHash returns the stored password hash of a user
+func FindPasswordHash(userID int64) (string, error) {
+	var hash string
+	err := db.DB.QueryRow(`SELECT password_hash FROM users WHERE id = ?`, userID).Scan(&hash)
+	if err != nil {
+		log.Println("Error finding password hash:", err)
+		return "", err
+	}
+	return hash, nil
+}
+
+// UpdatePasswordHash sets a new password and revokes every access token
+func UpdatePasswordHash(tx *sql.Tx, userID int64, hash string) error {
+	query := `UPDATE users SET password_hash = ?, token_version = token_version + 1 WHERE id = ?`
+	_, err := tx.Exec(query, hash, userID)
+	if err != nil {
+		log.Println("Error updating password:", err)
+	}
+	return err
+}
+
+// CreatePasswordReset stores the hash of an emailed reset token
+func CreatePasswordReset(userID int64, tokenHash string, expiresAt time.Time) error {
+	query := `INSERT INTO password_resets (user_id, token_hash, expires_at) VALUES (?, ?, ?)`
+	_, err := db.DB.Exec(query, userID, tokenHash, expiresAt)
+	if err != nil {
+		log.Println("Error creating password reset:", err)
+	}
+	return err
+}
+
+// FindPasswordReset looks up a reset token by its hash
+func FindPasswordReset(tokenHash string) (*PasswordReset, error) {
+	var r PasswordReset
+	var usedAt sql.NullTime
+	query := `SELECT id, user_id, expires_at, created_at, used_at FROM password_resets WHERE token_hash = ?`
+	err := db.DB.QueryRow(query, tokenHash).Scan(&r.ID, &r.UserID, &r.ExpiresAt, &r.CreatedAt, &usedAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error finding password reset:", err)
+		}
+		return nil, err
+	}
+	if usedAt.Valid {
+		r.UsedAt = &usedAt.Time
+	}
+	return &r, nil
+}
+
+// FindLatestPasswordResetAt returns when the user last requested a reset
+func FindLatestPasswordResetAt(userID int64) (time.Time, error) {
+	var at time.Time
+	query := `SELECT created_at FROM password_resets WHERE user_id = ? ORDER BY created_at DESC LIMIT 1`
+	err := db.DB.QueryRow(query, userID).Scan(&at)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error finding latest password reset:", err)
+	}
+	return at, err
+}
+
+// UsePasswordReset redeems a token. It reports false when the token was
+// already used (or is being redeemed concurrently).
+func UsePasswordReset(tx *sql.Tx, id int64) (bool, error) {
+	result, err := tx.Exec(`UPDATE password_resets SET used_at = NOW() WHERE id = ? AND used_at IS NULL`, id)
+	if err != nil {
+		log.Println("Error using password reset:", err)
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// ExpirePasswordResets invalidates a user's outstanding reset tokens
+func ExpirePasswordResets(tx *sql.Tx, userID int64) error {
+	query := `UPDATE password_resets SET used_at = NOW() WHERE user_id = ? AND used_at IS NULL`
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, userID)
+	} else {
+		_, err = db.DB.Exec(query, userID)
+	}
+	if err != nil {
+		log.Println("Error expiring password resets:", err)
+	}
+	return err
+}
+
+// CreatePhoneOTP stores a new one-time code
+func CreatePhoneOTP(o *PhoneOTP) error {
+	query := `
+		INSERT INTO phone_otps (phone, purpose, user_id, code_hash, request_ip, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	var userID sql.NullInt64
+	if o.UserID != 0 {
+		userID = sql.NullInt64{Int64: o.UserID, Valid: true}
+	}
+	result, err := db.DB.Exec(query, o.Phone, o.Purpose, userID, o.CodeHash, o.RequestIP, o.ExpiresAt)
+	if err != nil {
+		log.Println("Error creating phone OTP:", err)
+		return err
+	}
+	o.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// FindActivePhoneOTP returns the newest unused, unexpired code for a number
+func FindActivePhoneOTP(phone, purpose string) (*PhoneOTP, error) {
+	var o PhoneOTP
+	var userID sql.NullInt64
+	query := `
+		SELECT id, phone, purpose, user_id, code_hash, attempts, request_ip, expires_at
+		FROM phone_otps
+		WHERE phone = ? AND purpose = ? AND consumed_at IS NULL AND expires_at > ?
+		ORDER BY created_at DESC, id DESC LIMIT 1
+	`
+	err := db.DB.QueryRow(query, phone, purpose, time.Now()).Scan(
+		&o.ID, &o.Phone, &o.Purpose, &userID, &o.CodeHash, &o.Attempts, &o.RequestIP, &o.ExpiresAt,
+	)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error finding phone OTP:", err)
+		}
+		return nil, err
+	}
+	o.UserID = userID.Int64
+	return &o, nil
+}
+
+// CountPhoneOTPsSince counts codes sent to a number since a time
+func CountPhoneOTPsSince(phone string, since time.Time) (int, time.Time, error) {
+	var n int
+	var latest sql.NullTime
+	query := `SELECT COUNT(*), MAX(created_at) FROM phone_otps WHERE phone = ? AND created_at > ?`
+	err := db.DB.QueryRow(query, phone, since).Scan(&n, &latest)
+	if err != nil {
+		log.Println("Error counting phone OTPs:", err)
+		return 0, time.Time{}, err
+	}
+	return n, latest.Time, nil
+}
+
+// CountIPOTPsSince counts codes requested from an IP address since a time
+func CountIPOTPsSince(ip string, since time.Time) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM phone_otps WHERE request_ip = ? AND created_at > ?`
+	err := db.DB.QueryRow(query, ip, since).Scan(&n)
+	if err != nil {
+		log.Println("Error counting OTPs by IP:", err)
+	}
+	return n, err
+}
+
+// ClaimOTPAttempt records a guess at a code. It reports false when the code
+// is used up or has no guesses left, so concurrent guesses can't go past max.
+func ClaimOTPAttempt(id int64, max int) (bool, error) {
+	query := `UPDATE phone_otps SET attempts = attempts + 1 WHERE id = ? AND attempts < ? AND consumed_at IS NULL`
+	result, err := db.DB.Exec(query, id, max)
+	if err != nil {
+		log.Println("Error counting OTP attempt:", err)
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// RecordOTPFailure logs a wrong code for a number from an IP address
+func RecordOTPFailure(phone, ip string) error {
+	_, err := db.DB.Exec(`INSERT INTO otp_failures (phone, ip) VALUES (?, ?)`, phone, ip)
+	if err != nil {
+		log.Println("Error recording OTP failure:", err)
+	}
+	return err
+}
+
+// CountOTPFailuresSince counts wrong codes for a number and from an IP
+// address since a time
+func CountOTPFailuresSince(phone, ip string, since time.Time) (byPhone, byIP int, err error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM otp_failures WHERE phone = ? AND created_at > ?),
+			(SELECT COUNT(*) FROM otp_failures WHERE ip = ? AND created_at > ?)
+	`
+	err = db.DB.QueryRow(query, phone, since, ip, since).Scan(&byPhone, &byIP)
+	if err != nil {
+		log.Println("Error counting OTP failures:", err)
+	}
+	return byPhone, byIP, err
+}
+
+// ConsumePhoneOTP uses up a code. It reports false when it was already used.
+func ConsumePhoneOTP(id int64) (bool, error) {
+	result, err := db.DB.Exec(`UPDATE phone_otps SET consumed_at = NOW() WHERE id = ? AND consumed_at IS NULL`, id)
+	if err != nil {
+		log.Println("Error consuming phone OTP:", err)
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// ExpirePhoneOTPs invalidates the outstanding codes of a number
+func ExpirePhoneOTPs(phone, purpose string) error {
+	query := `UPDATE phone_otps SET consumed_at = NOW() WHERE phone = ? AND purpose = ? AND consumed_at IS NULL`
+	_, err := db.DB.Exec(query, phone, purpose)
+	if err != nil {
+		log.Println("Error expiring phone OTPs:", err)
+	}
+	return err
+}
+
+// MarkPhoneVerified records that the user proved they hold the number on
+// their profile
+func MarkPhoneVerified(userID int64, phone string) error {
+	query := `UPDATE users SET phone_verified_at = NOW() WHERE id = ? AND phone = ? AND phone_verified_at IS NULL`
+	_, err := db.DB.Exec(query, userID, phone)
+	if err != nil {
+		log.Println("Error marking phone verified:", err)
+	}
+	return err
+}
@@ -2,12 +2,16 @@
 package user
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"errors"
+	"io"
 	"log"
-	"os"
-	"time"
 
+	"github.com/JkD004/playarena-backend/imaging"
+	"github.com/JkD004/playarena-backend/rating"
+	"github.com/JkD004/playarena-backend/storage"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -17,15 +21,18 @@ type Claims struct {
 	UserID int64  `json:"id"`
 	Email  string `json:"email"`
 	Role   string `json:"role"`
+	// TokenVersion must match users.token_version; bumping it revokes the token
+	TokenVersion int `json:"tv"`
 	jwt.RegisteredClaims
 }
 
 // RegisterNewUser function
 func RegisterNewUser(user *User) error {
+	user.Phone = normalizePhone(user.Phone)
 	if user.Phone != "" {
 		_, err := FindUserByPhone(user.Phone)
 		if err == nil {
-			return errors.New("phone number already in use")
+			return ErrPhoneInUse
 		}
 		if err != sql.ErrNoRows {
 			log.Println("DB error checking phone:", err)
@@ -44,51 +51,56 @@ func RegisterNewUser(user *User) error {
 	if err != nil {
 		return errors.New("failed to create user, email may be taken")
 	}
+
+	// The account exists either way; a failed email can be resent
+	if err := sendVerificationEmail(user.ID, user.Email, user.FirstName); err != nil {
+		log.Println("Error sending verification email to user", user.ID, ":", err)
+	}
 	return nil
 }
 
-// 2. UPDATE LoginUser TO RETURN TOKEN AND ROLE
-func LoginUser(email, password string) (string, string, error) {
+// LoginUser checks the credentials and starts a new session (token family)
+func LoginUser(email, password string) (*TokenPair, error) {
 	storedUser, err := FindUserByEmail(email)
 	if err != nil {
-		return "", "", errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(storedUser.PasswordHash), []byte(password))
 	if err != nil {
-		return "", "", errors.New("invalid email or password")
+		return nil, errors.New("invalid email or password")
 	}
-
-	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		UserID: storedUser.ID,
-		Email:  storedUser.Email,
-		Role:   storedUser.Role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
+	if storedUser.Status == StatusSuspended {
+		return nil, ErrAccountSuspended
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// --- SECURITY FIX: Read from ENV ---
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		// --- FIX WAS HERE: Added extra "" to match signature ---
-		return "", "", errors.New("server error: JWT_SECRET not set")
-	}
-	tokenString, err := token.SignedString([]byte(secret))
-	// -----------------------------------
-	
+	familyID, err := newFamilyID()
 	if err != nil {
-		return "", "", errors.New("could not generate token")
+		return nil, errors.New("could not generate token")
 	}
-
-	return tokenString, storedUser.Role, nil
+	return issueTokens(nil, &AuthState{
+		UserID:       storedUser.ID,
+		Email:        storedUser.Email,
+		Role:         storedUser.Role,
+		Status:       storedUser.Status,
+		TokenVersion: storedUser.TokenVersion,
+	}, familyID)
 }
 
 func GetUserProfile(userID int64) (*User, error) {
-	return FindUserByID(userID)
+	user, err := FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach sport ratings; a failure here shouldn't break the profile
+	ratings, err := rating.GetUserRatings(userID)
+	if err != nil {
+		log.Println("Error fetching ratings for profile:", err)
+	} else {
+		user.Ratings = ratings
+	}
+	return user, nil
 }
 
 // UpdateUserProfile handles validation and saving
@@ -101,15 +113,67 @@ func UpdateUserProfile(userID int64, updates *User) error {
 
 	// 2. Set the ID on the update struct so repository knows who to update
 	updates.ID = userID
-	
+	updates.Phone = normalizePhone(updates.Phone)
+	if updates.Phone != "" {
+		holder, err := FindUserByPhone(updates.Phone)
+		if err == nil && holder.ID != userID {
+			return ErrPhoneInUse
+		}
+		if err != nil && err != sql.ErrNoRows {
+			return errors.New("database error checking phone number")
+		}
+	}
+
 	// 3. Save changes
-	return UpdateUser(updates)
+	if err := UpdateUser(updates); err != nil {
+		// Someone may have taken the number since the check (unique index)
+		if holder, findErr := FindUserByPhone(updates.Phone); findErr == nil && holder.ID != userID {
+			return ErrPhoneInUse
+		}
+		return err
+	}
+	return nil
 }
 
 func GetUserByEmail(email string) (*User, error) {
 	return FindUserByEmail(email)
 }
 
-func UpdateAvatar(userID int64, url string) error {
-	return UpdateUserAvatar(userID, url)
+// MaxAvatarBytes caps the size of an uploaded avatar
+const MaxAvatarBytes = 5 << 20 // 5 MB
+
+// UpdateAvatar validates the upload, re-encodes it as a square avatar
+// (dropping EXIF/GPS metadata), stores it and deletes the previous one
+func UpdateAvatar(userID int64, src io.Reader) (string, error) {
+	img, err := imaging.Decode(src, MaxAvatarBytes)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := imaging.Render(img, imaging.Avatar)
+	if err != nil {
+		log.Println("Error rendering avatar:", err)
+		return "", errors.New("failed to process image")
+	}
+
+	oldKey, err := FindUserAvatarKey(userID)
+	if err != nil {
+		return "", errors.New("user not found")
+	}
+
+	obj, err := store.Put(context.Background(), "playarena_users", bytes.NewReader(encoded[0].Data), imaging.ContentType)
+	if err != nil {
+		log.Println("Error uploading avatar:", err)
+		return "", errors.New("failed to upload image")
+	}
+
+	if err := UpdateUserAvatar(userID, obj.URL, obj.Key); err != nil {
+		storage.DeleteLater(store, obj.Key)
+		return "", errors.New("failed to save avatar URL")
+	}
+
+	// Avatars uploaded before the storage package have no key
+	if oldKey != "" {
+		storage.DeleteLater(store, oldKey)
+	}
+	return obj.URL, nil
 }
\ No newline at end of file
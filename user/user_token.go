@@ -0,0 +1,197 @@
+// user/user_token.go
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/jwtkeys"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReuse   = errors.New("refresh token was already used; please log in again")
+)
+
+// accessTokenTTL reads ACCESS_TOKEN_TTL (e.g. "15m")
+func accessTokenTTL() time.Duration {
+	if d := envDuration("ACCESS_TOKEN_TTL", defaultAccessTokenTTL); d > 0 {
+		return d
+	}
+	return defaultAccessTokenTTL
+}
+
+// refreshTokenTTL reads REFRESH_TOKEN_TTL (e.g. "720h")
+func refreshTokenTTL() time.Duration {
+	if d := envDuration("REFRESH_TOKEN_TTL", defaultRefreshTokenTTL); d > 0 {
+		return d
+	}
+	return defaultRefreshTokenTTL
+}
+
+// randomToken returns n random bytes, base64url encoded
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// newFamilyID names the token family of a new login
+func newFamilyID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken is how refresh tokens are stored and looked up
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// signAccessToken issues a short-lived access token for the account state
+func signAccessToken(s *AuthState, ttl time.Duration) (string, error) {
+	claims := &Claims{
+		UserID:       s.UserID,
+		Email:        s.Email,
+		Role:         s.Role,
+		TokenVersion: s.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+	signed, err := jwtkeys.Sign(claims)
+	if err != nil {
+		log.Println("Error signing access token:", err)
+		return "", errors.New("could not generate token")
+	}
+	return signed, nil
+}
+
+// issueTokens creates an access token and the next refresh token of the
+// family (inside tx when one is given)
+func issueTokens(tx *sql.Tx, s *AuthState, familyID string) (*TokenPair, error) {
+	ttl := accessTokenTTL()
+	access, err := signAccessToken(s, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := randomToken(32)
+	if err != nil {
+		return nil, errors.New("could not generate token")
+	}
+	if err := CreateRefreshToken(tx, s.UserID, familyID, hashToken(refresh), time.Now().Add(refreshTokenTTL())); err != nil {
+		return nil, errors.New("could not save session")
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(ttl.Seconds()),
+		Role:         s.Role,
+	}, nil
+}
+
+// RefreshTokens rotates a refresh token: it is marked used and a new pair is
+// issued with the user's current role. A token that was already rotated is
+// being replayed (a stolen copy, or the thief got there first), so the whole
+// family is revoked and both parties have to log in again.
+func RefreshTokens(refreshToken string) (*TokenPair, error) {
+	stored, err := FindRefreshToken(hashToken(refreshToken))
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if stored.UsedAt != nil {
+		log.Printf("Refresh token reuse for user %d; revoking family %s", stored.UserID, stored.FamilyID)
+		_ = RevokeRefreshFamily(stored.FamilyID)
+		return nil, ErrRefreshTokenReuse
+	}
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	s, err := FindAuthState(stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if s.Status == StatusSuspended {
+		return nil, ErrAccountSuspended
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return nil, errors.New("database error")
+	}
+	defer tx.Rollback()
+
+	rotated, err := MarkRefreshTokenUsed(tx, stored.ID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if !rotated {
+		// Lost a race with another refresh of the same token
+		tx.Rollback()
+		_ = RevokeRefreshFamily(stored.FamilyID)
+		return nil, ErrRefreshTokenReuse
+	}
+	pair, err := issueTokens(tx, s, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("database error")
+	}
+	return pair, nil
+}
+
+// Logout ends the session a refresh token belongs to. Access tokens already
+// issued to it run out on their own within ACCESS_TOKEN_TTL.
+func Logout(refreshToken string) error {
+	stored, err := FindRefreshToken(hashToken(refreshToken))
+	if err == sql.ErrNoRows {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return errors.New("database error")
+	}
+	if err := RevokeRefreshFamily(stored.FamilyID); err != nil {
+		return errors.New("failed to log out")
+	}
+	return nil
+}
+
+// LogoutAll ends every session of a user on every device, including access
+// tokens that haven't expired yet
+func LogoutAll(userID int64) error {
+	if err := RevokeUserRefreshTokens(nil, userID); err != nil {
+		return errors.New("failed to log out")
+	}
+	if err := BumpTokenVersion(userID); err != nil {
+		return errors.New("failed to log out")
+	}
+	InvalidateAuthState(userID)
+	return nil
+}
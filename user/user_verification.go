@@ -0,0 +1,150 @@
+// user/user_verification.go
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/mailer"
+)
+
+const (
+	// emailVerificationTTL is how long a verification link works
+	emailVerificationTTL = 24 * time.Hour
+
+	// resendCooldown is the minimum wait between two verification emails
+	resendCooldown = time.Minute
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification link")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrResendTooSoon            = errors.New("a verification email was sent recently; please wait a minute")
+)
+
+// Global mailer for User package (verification and password emails)
+var mail mailer.Mailer
+
+func SetMailer(m mailer.Mailer) {
+	mail = m
+}
+
+// appBaseURL reads APP_BASE_URL, the public address links in emails point to
+func appBaseURL() string {
+	base := os.Getenv("APP_BASE_URL")
+	if base == "" {
+		base = "http://localhost:8080"
+	}
+	return strings.TrimSuffix(base, "/")
+}
+
+// sendMail sends through the configured mailer, logging instead when none is set
+func sendMail(msg mailer.Message) error {
+	if mail == nil {
+		log.Printf("No mailer configured; dropping mail to %s: %s", msg.To, msg.Subject)
+		return errors.New("mailer not configured")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := mail.Send(ctx, msg); err != nil {
+		log.Printf("Error sending mail to %s: %v", msg.To, err)
+		return err
+	}
+	return nil
+}
+
+// sendVerificationEmail issues a new verification token and emails the link.
+// Older tokens of the user stop working.
+func sendVerificationEmail(userID int64, email, firstName string) error {
+	token, err := randomToken(32)
+	if err != nil {
+		return errors.New("could not generate token")
+	}
+	if err := DeleteEmailVerifications(nil, userID); err != nil {
+		return errors.New("database error")
+	}
+	if err := CreateEmailVerification(userID, hashToken(token), time.Now().Add(emailVerificationTTL)); err != nil {
+		return errors.New("database error")
+	}
+
+	link := appBaseURL() + "/api/v1/verify-email?token=" + url.QueryEscape(token)
+	greeting := "Hi"
+	if firstName != "" {
+		greeting += " " + firstName
+	}
+	return sendMail(mailer.Message{
+		To:      email,
+		Subject: "Confirm your PlayArena email",
+		Body: greeting + ",\n\n" +
+			"Please confirm your email address by opening this link:\n\n" + link + "\n\n" +
+			"The link expires in 24 hours. If you didn't create a PlayArena account, you can ignore this email.\n",
+	})
+}
+
+// VerifyEmail confirms the email of the user a token was sent to
+func VerifyEmail(token string) error {
+	if token == "" {
+		return ErrInvalidVerificationToken
+	}
+	v, err := FindEmailVerification(hashToken(token))
+	if err == sql.ErrNoRows {
+		return ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return errors.New("database error")
+	}
+	if time.Now().After(v.ExpiresAt) {
+		return ErrInvalidVerificationToken
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return errors.New("database error")
+	}
+	defer tx.Rollback()
+
+	if err := MarkEmailVerified(tx, v.UserID); err != nil {
+		return errors.New("failed to verify email")
+	}
+	if err := DeleteEmailVerifications(tx, v.UserID); err != nil {
+		return errors.New("failed to verify email")
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.New("failed to verify email")
+	}
+
+	// Bookings and venue listings open up on the next request
+	InvalidateAuthState(v.UserID)
+	return nil
+}
+
+// ResendVerificationEmail sends a fresh verification link
+func ResendVerificationEmail(userID int64) error {
+	u, err := FindUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if u.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+	last, err := FindLatestEmailVerification(userID)
+	if err != nil && err != sql.ErrNoRows {
+		return errors.New("database error")
+	}
+	if last != nil && time.Since(last.CreatedAt) < resendCooldown {
+		return ErrResendTooSoon
+	}
+
+	if err := sendVerificationEmail(u.ID, u.Email, u.FirstName); err != nil {
+		return errors.New("failed to send verification email")
+	}
+	return nil
+}
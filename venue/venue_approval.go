@@ -0,0 +1,189 @@
+// venue/venue_approval.go
+package venue
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/notification"
+	"github.com/JkD004/playarena-backend/user"
+)
+
+// Venue approval states
+const (
+	StatusPending          = "pending"
+	StatusApproved         = "approved"
+	StatusRejected         = "rejected"
+	StatusChangesRequested = "changes_requested" // owner must edit and resubmit
+)
+
+// adminTransitions lists the statuses an admin decision may move a venue
+// from, keyed by the decision
+var adminTransitions = map[string][]string{
+	StatusApproved:         {StatusPending, StatusRejected, StatusChangesRequested},
+	StatusRejected:         {StatusPending, StatusChangesRequested, StatusApproved},
+	StatusChangesRequested: {StatusPending},
+}
+
+// Approval errors the handlers map to specific HTTP statuses
+var (
+	ErrVenueNotFound     = errors.New("venue not found")
+	ErrInvalidTransition = errors.New("this status change is not allowed")
+)
+
+func canTransition(decision, from string) bool {
+	for _, s := range adminTransitions[decision] {
+		if s == from {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateVenueStatus records an admin decision on a venue. Rejections and
+// change requests must carry a note, which is shown to the owner.
+// Approving a venue also upgrades its owner to the 'owner' role.
+func UpdateVenueStatus(venueID, adminID int64, newStatus, note string) error {
+	note = strings.TrimSpace(note)
+	if _, ok := adminTransitions[newStatus]; !ok {
+		return errors.New("status must be 'approved', 'rejected' or 'changes_requested'")
+	}
+	if newStatus != StatusApproved && note == "" {
+		return errors.New("a note explaining the decision is required")
+	}
+
+	v, err := FindVenueByID(venueID)
+	if err != nil {
+		return ErrVenueNotFound
+	}
+	if !canTransition(newStatus, v.Status) {
+		return ErrInvalidTransition
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	updated, err := UpdateVenueStatusInDB(tx, venueID, v.Status, newStatus, note)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		// Another decision or a resubmission changed the status meanwhile
+		return ErrInvalidTransition
+	}
+	if err := CreateStatusHistory(tx, &VenueStatusChange{
+		VenueID:    venueID,
+		ActorID:    adminID,
+		ActorRole:  "admin",
+		FromStatus: v.Status,
+		ToStatus:   newStatus,
+		Note:       note,
+	}); err != nil {
+		return err
+	}
+
+	if newStatus == StatusApproved {
+		log.Printf("Venue %d approved. Upgrading user %d to 'owner'", venueID, v.OwnerID)
+		if err := user.UpdateUserRole(tx, v.OwnerID, "owner"); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if newStatus == StatusApproved {
+		user.InvalidateAuthState(v.OwnerID)
+	}
+
+	_ = notification.CreateNotification(v.OwnerID, decisionMessage(v.Name, newStatus, note), decisionType(newStatus))
+	return nil
+}
+
+func decisionMessage(venueName, status, note string) string {
+	var msg string
+	switch status {
+	case StatusApproved:
+		msg = "Your venue " + venueName + " has been APPROVED! You are now an Owner."
+	case StatusRejected:
+		msg = "Your venue listing " + venueName + " was rejected by the admin."
+	case StatusChangesRequested:
+		msg = "The admin requested changes to your venue " + venueName + ". Please update it and resubmit."
+	}
+	if note != "" {
+		msg += " Note: " + note
+	}
+	return msg
+}
+
+func decisionType(status string) string {
+	switch status {
+	case StatusApproved:
+		return "success"
+	case StatusRejected:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// ResubmitVenue sends a rejected venue, or one with requested changes,
+// back to the admin queue
+func ResubmitVenue(venueID, ownerID int64, note string) error {
+	v, err := FindVenueByID(venueID)
+	if err != nil {
+		return ErrVenueNotFound
+	}
+	if v.Status != StatusRejected && v.Status != StatusChangesRequested {
+		return errors.New("only rejected venues or venues with requested changes can be resubmitted")
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	updated, err := UpdateVenueStatusInDB(tx, venueID, v.Status, StatusPending, "")
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrInvalidTransition
+	}
+	if err := CreateStatusHistory(tx, &VenueStatusChange{
+		VenueID:    venueID,
+		ActorID:    ownerID,
+		ActorRole:  "owner",
+		FromStatus: v.Status,
+		ToStatus:   StatusPending,
+		Note:       strings.TrimSpace(note),
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// recordSubmission adds the initial "submitted" entry for a new venue, in
+// the transaction that creates it
+func recordSubmission(tx *sql.Tx, v *Venue) error {
+	return CreateStatusHistory(tx, &VenueStatusChange{
+		VenueID:   v.ID,
+		ActorID:   v.OwnerID,
+		ActorRole: "owner",
+		ToStatus:  v.Status,
+	})
+}
+
+// GetVenueStatusHistory returns the approval history of a venue
+func GetVenueStatusHistory(venueID int64) ([]VenueStatusChange, error) {
+	return FindStatusHistory(venueID)
+}
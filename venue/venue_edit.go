@@ -0,0 +1,212 @@
+// venue/venue_edit.go
+package venue
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrVersionConflict is returned when a venue was changed after the client
+// last read it
+var ErrVersionConflict = errors.New("venue was modified by someone else; reload and try again")
+
+// ErrVersionRequired is returned when an edit doesn't say which version of
+// the venue it was made against
+var ErrVersionRequired = errors.New("send the venue's ETag in If-Match (or its version) to edit it")
+
+// AnyVersion skips the version check; clients ask for it with "If-Match: *"
+const AnyVersion = -1
+
+// checkVersion makes every edit state the version it was based on, so two
+// editors can't silently overwrite each other
+func checkVersion(expected, current int) error {
+	switch {
+	case expected == AnyVersion:
+		return nil
+	case expected <= 0:
+		return ErrVersionRequired
+	case expected != current:
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// validatePrice rejects free or negative hourly prices
+func validatePrice(price float64) error {
+	if price <= 0 {
+		return errors.New("price_per_hour must be greater than 0")
+	}
+	return nil
+}
+
+// parseTimeOfDay accepts HH:MM (and the HH:MM:SS form the database returns)
+// and returns it as HH:MM:SS. "24:00" is allowed as a closing time of midnight.
+func parseTimeOfDay(field, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" || value == "24:00:00" {
+		return "24:00:00", nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("15:04:05"), nil
+		}
+	}
+	return "", errors.New(field + " must be HH:MM")
+}
+
+// overnightCutoff is the latest a venue can close after midnight. Later
+// closing times before the opening time are taken as typos, not as a venue
+// open nearly round the clock.
+const overnightCutoff = "06:00:00"
+
+// normalizeHours checks the venue's opening hours and lunch break and rewrites
+// them as HH:MM:SS. Closing must come after opening, except that a venue may
+// close after midnight up to overnightCutoff (18:00-02:00), as venue search
+// assumes; "00:00" closes at midnight. The lunch break is optional but must have both ends
+// and sit inside the hours.
+func normalizeHours(v *Venue) error {
+	opening, err := parseTimeOfDay("opening_time", v.OpeningTime)
+	if err != nil {
+		return err
+	}
+	closing, err := parseTimeOfDay("closing_time", v.ClosingTime)
+	if err != nil {
+		return err
+	}
+	if opening == "24:00:00" {
+		return errors.New("opening_time must be HH:MM")
+	}
+	if closing == "00:00:00" {
+		closing = "24:00:00"
+	}
+	if closing == opening {
+		return errors.New("closing_time must differ from opening_time")
+	}
+	overnight := closing < opening
+	if overnight && closing > overnightCutoff {
+		return errors.New("closing_time must be after opening_time (or no later than 06:00 for venues open past midnight)")
+	}
+	v.OpeningTime, v.ClosingTime = opening, closing
+
+	if v.LunchStart == "" && v.LunchEnd == "" {
+		return nil
+	}
+	if v.LunchStart == "" || v.LunchEnd == "" {
+		return errors.New("lunch_start_time and lunch_end_time must be provided together")
+	}
+	lunchStart, err := parseTimeOfDay("lunch_start_time", v.LunchStart)
+	if err != nil {
+		return err
+	}
+	lunchEnd, err := parseTimeOfDay("lunch_end_time", v.LunchEnd)
+	if err != nil {
+		return err
+	}
+	if lunchEnd <= lunchStart {
+		return errors.New("lunch_end_time must be after lunch_start_time")
+	}
+	// Overnight, the break falls either in the evening part or after midnight
+	inside := lunchStart >= opening && lunchEnd <= closing
+	if overnight {
+		inside = lunchStart >= opening || lunchEnd <= closing
+	}
+	if !inside {
+		return errors.New("lunch break must be within opening hours")
+	}
+	v.LunchStart, v.LunchEnd = lunchStart, lunchEnd
+	return nil
+}
+
+// validateVenue checks the fields every full venue write must satisfy
+func validateVenue(v *Venue) error {
+	if strings.TrimSpace(v.Name) == "" {
+		return errors.New("name is required")
+	}
+	if err := validatePrice(v.PricePerHour); err != nil {
+		return err
+	}
+	if err := normalizeHours(v); err != nil {
+		return err
+	}
+	return ValidateCoordinates(v.Latitude, v.Longitude)
+}
+
+// PatchVenue applies a partial edit. Fields missing from the patch keep their
+// current values, and only the fields that are sent are validated (so an
+// older venue with odd stored hours can still have its price changed).
+// expectedVersion is the version the client last saw (or AnyVersion).
+// Sensitive changes to an approved venue are staged as in ModifyVenue.
+func PatchVenue(venueID int64, patch *VenuePatch, expectedVersion int, userID int64, userRole string) (*Venue, *VenueRevision, error) {
+	live, err := loadVenue(venueID)
+	if err != nil {
+		return nil, nil, ErrVenueNotFound
+	}
+	if err := checkVersion(expectedVersion, live.Version); err != nil {
+		return nil, nil, err
+	}
+
+	edit := *live
+	edit.Amenities = nil // unchanged unless the patch sends a list
+	if patch.Name != nil {
+		if strings.TrimSpace(*patch.Name) == "" {
+			return nil, nil, errors.New("name cannot be empty")
+		}
+		edit.Name = *patch.Name
+	}
+	if patch.Sports != nil {
+		edit.Sports = patch.Sports
+	}
+	if patch.Description != nil {
+		edit.Description = *patch.Description
+	}
+	if patch.Address != nil {
+		edit.Address = *patch.Address
+	}
+	if patch.PricePerHour != nil {
+		if err := validatePrice(*patch.PricePerHour); err != nil {
+			return nil, nil, err
+		}
+		edit.PricePerHour = *patch.PricePerHour
+	}
+	if patch.Latitude != nil || patch.Longitude != nil {
+		if err := ValidateCoordinates(patch.Latitude, patch.Longitude); err != nil {
+			return nil, nil, err
+		}
+		edit.Latitude, edit.Longitude = patch.Latitude, patch.Longitude
+	}
+	if patch.Amenities != nil {
+		edit.Amenities = patch.Amenities
+	}
+
+	hoursChanged := false
+	for _, f := range []struct {
+		value *string
+		dest  *string
+	}{
+		{patch.OpeningTime, &edit.OpeningTime},
+		{patch.ClosingTime, &edit.ClosingTime},
+		{patch.LunchStart, &edit.LunchStart},
+		{patch.LunchEnd, &edit.LunchEnd},
+	} {
+		if f.value != nil {
+			*f.dest = *f.value
+			hoursChanged = true
+		}
+	}
+	if hoursChanged {
+		if err := normalizeHours(&edit); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	revision, err := saveVenueEdit(live, &edit, userID, userRole)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated, err := loadVenue(venueID)
+	if err != nil {
+		return nil, nil, errors.New("venue updated but could not be reloaded")
+	}
+	return updated, revision, nil
+}
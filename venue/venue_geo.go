@@ -0,0 +1,85 @@
+// venue/venue_geo.go
+package venue
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Distances are computed by the database: BoundingBox narrows the rows with
+// an index-friendly range check and haversineSQL gives the exact distance
+// that results are filtered and sorted by.
+const earthRadiusKm = 6371.0
+
+// haversineSQL is the exact great-circle distance (km) from a point to a venue.
+// Its placeholders take (lat, lng, lat).
+var haversineSQL = fmt.Sprintf(`(%g * ACOS(LEAST(1,
+		COS(RADIANS(?)) * COS(RADIANS(v.latitude)) * COS(RADIANS(v.longitude) - RADIANS(?))
+		+ SIN(RADIANS(?)) * SIN(RADIANS(v.latitude)))))`, earthRadiusKm)
+
+// Limits for GET /venues?near=...&radius_km=...
+const (
+	DefaultRadiusKm = 10.0
+	MaxRadiusKm     = 100.0
+)
+
+// ValidateCoordinates checks that a venue has both or neither coordinate, in range
+func ValidateCoordinates(lat, lng *float64) error {
+	if lat == nil && lng == nil {
+		return nil
+	}
+	if lat == nil || lng == nil {
+		return errors.New("latitude and longitude must be provided together")
+	}
+	if *lat < -90 || *lat > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if *lng < -180 || *lng > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+// ParseLatLng parses a "lat,lng" query value
+func ParseLatLng(s string) (float64, float64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("near must be in the form lat,lng")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, errors.New("invalid latitude in near")
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, errors.New("invalid longitude in near")
+	}
+	if err := ValidateCoordinates(&lat, &lng); err != nil {
+		return 0, 0, err
+	}
+	return lat, lng, nil
+}
+
+// BoundingBox returns the lat/lng rectangle enclosing a circle of radiusKm.
+// It is used as a cheap, index-friendly prefilter before the exact check.
+func BoundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	dLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	minLat, maxLat = lat-dLat, lat+dLat
+
+	// Near the poles the longitude span covers everything
+	cosLat := math.Cos(lat * math.Pi / 180)
+	if maxLat >= 90 || minLat <= -90 || cosLat < 1e-9 {
+		return math.Max(minLat, -90), math.Min(maxLat, 90), -180, 180
+	}
+
+	dLng := dLat / cosLat
+	minLng, maxLng = lng-dLng, lng+dLng
+	if minLng < -180 || maxLng > 180 {
+		// Crossing the antimeridian; fall back to the full longitude range
+		return minLat, maxLat, -180, 180
+	}
+	return minLat, maxLat, minLng, maxLng
+}
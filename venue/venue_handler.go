@@ -2,21 +2,23 @@
 package venue
 
 import (
-	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
-	"github.com/JkD004/playarena-backend/db"
-	"github.com/cloudinary/cloudinary-go/v2"
-	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/JkD004/playarena-backend/imaging"
+	"github.com/JkD004/playarena-backend/storage"
 	"github.com/gin-gonic/gin"
 )
 
-var cld *cloudinary.Cloudinary
+// store holds venue and review photos
+var store storage.Storage
 
-func SetCloudinary(cloudinaryClient *cloudinary.Cloudinary) {
-	cld = cloudinaryClient
+func SetStorage(s storage.Storage) {
+	store = s
 }
 
 // -------------------------------------------------------
@@ -34,7 +36,11 @@ func CreateVenueHandler(c *gin.Context) {
 
 	err := CreateNewVenue(&venue, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create venue"})
+		status := http.StatusBadRequest
+		if errors.Is(err, ErrVenueSaveFailed) {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -42,17 +48,88 @@ func CreateVenueHandler(c *gin.Context) {
 }
 
 // -------------------------------------------------------
-// GET ALL VENUES (admin)
+// SEARCH VENUES (public)
+// GET /venues?sport=&min_price=&max_price=&min_rating=&open_at=HH:MM
+//            &amenity=parking&amenity=...&q=&near=lat,lng&radius_km=
+//            &sort=newest|price|price_desc|rating|distance&cursor=&limit=
+// The body stays a plain array; the next page cursor is sent in X-Next-Cursor.
 // -------------------------------------------------------
 func GetVenuesHandler(c *gin.Context) {
-	venues, err := GetAllVenues()
+	params, err := ParseVenueSearchParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch venues"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	venues, next, err := SearchVenues(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+
+	if next != "" {
+		c.Header("X-Next-Cursor", next)
+	}
 	c.JSON(http.StatusOK, venues)
 }
 
+// ParseVenueSearchParams reads the search query string
+func ParseVenueSearchParams(c *gin.Context) (*VenueSearchParams, error) {
+	p := &VenueSearchParams{
+		Sport:     c.Query("sport"),
+		OpenAt:    c.Query("open_at"),
+		Amenities: normalizeAmenities(c.QueryArray("amenity")),
+		Query:     strings.TrimSpace(c.Query("q")),
+		Sort:      c.Query("sort"),
+		Cursor:    c.Query("cursor"),
+	}
+
+	floatParam := func(name string) (*float64, error) {
+		raw := c.Query(name)
+		if raw == "" {
+			return nil, nil
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, errors.New(name + " must be a number")
+		}
+		return &f, nil
+	}
+
+	var err error
+	if p.MinPrice, err = floatParam("min_price"); err != nil {
+		return nil, err
+	}
+	if p.MaxPrice, err = floatParam("max_price"); err != nil {
+		return nil, err
+	}
+	if p.MinRating, err = floatParam("min_rating"); err != nil {
+		return nil, err
+	}
+	if raw := c.Query("limit"); raw != "" {
+		if p.Limit, err = strconv.Atoi(raw); err != nil {
+			return nil, errors.New("limit must be a number")
+		}
+	}
+
+	if near := c.Query("near"); near != "" {
+		lat, lng, err := ParseLatLng(near)
+		if err != nil {
+			return nil, err
+		}
+		p.Lat, p.Lng = &lat, &lng
+
+		p.RadiusKm = DefaultRadiusKm
+		if r := c.Query("radius_km"); r != "" {
+			p.RadiusKm, err = strconv.ParseFloat(r, 64)
+			if err != nil || p.RadiusKm <= 0 || p.RadiusKm > MaxRadiusKm {
+				return nil, errors.New("radius_km must be between 0 and 100")
+			}
+		}
+	}
+	return p, nil
+}
+
 // -------------------------------------------------------
 // GET VENUES BY STATUS (admin)
 // -------------------------------------------------------
@@ -77,27 +154,71 @@ func UpdateVenueStatusHandler(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Status string `json:"status" binding:"required"`
-	}
-
+	var req VenueDecisionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body, 'status' is required"})
 		return
 	}
 
-	if req.Status != "approved" && req.Status != "rejected" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be 'approved' or 'rejected'"})
+	adminID := c.MustGet("userID").(int64)
+	err = UpdateVenueStatus(venueID, adminID, req.Status, req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrVenueNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	err = UpdateVenueStatus(venueID, req.Status)
+	c.JSON(http.StatusOK, gin.H{"message": "Venue status updated successfully"})
+}
+
+// -------------------------------------------------------
+// RESUBMIT VENUE / APPROVAL HISTORY (owner or admin)
+// -------------------------------------------------------
+
+// ResubmitVenueHandler handles POST /api/v1/venues/:id/resubmit
+func ResubmitVenueHandler(c *gin.Context) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update venue status"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Venue status updated successfully"})
+	userID := c.MustGet("userID").(int64)
+
+	var req ResubmitVenueRequest
+	_ = c.ShouldBindJSON(&req) // the note is optional
+
+	if err := ResubmitVenue(venueID, userID, req.Note); err != nil {
+		if errors.Is(err, ErrVenueNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Venue resubmitted for review"})
+}
+
+// GetVenueStatusHistoryHandler handles GET /api/v1/venues/:id/history
+func GetVenueStatusHistoryHandler(c *gin.Context) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	history, err := GetVenueStatusHistory(venueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch venue history"})
+		return
+	}
+	c.JSON(http.StatusOK, history)
 }
 
 // -------------------------------------------------------
@@ -120,9 +241,35 @@ func GetVenueByIDHandler(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", venueETag(venue.Version))
 	c.JSON(http.StatusOK, venue)
 }
 
+// venueETag formats a venue version as a strong ETag, e.g. "7"
+func venueETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// expectedVersion reads the version a client is editing from the If-Match
+// header, falling back to the version sent in the body. "If-Match: *" is
+// AnyVersion; 0 means the client sent neither, which edits reject with 428.
+// ok is false when If-Match is present but unreadable.
+func expectedVersion(c *gin.Context, bodyVersion int) (int, bool) {
+	match := strings.TrimSpace(c.GetHeader("If-Match"))
+	if match == "" {
+		return bodyVersion, true
+	}
+	if match == "*" {
+		return AnyVersion, true
+	}
+	match = strings.Trim(strings.TrimPrefix(match, "W/"), `"`)
+	version, err := strconv.Atoi(match)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
 // -------------------------------------------------------
 // UPLOAD VENUE PHOTO
 // -------------------------------------------------------
@@ -130,17 +277,10 @@ func UploadVenuePhotoHandler(c *gin.Context) {
 	venueIDStr := c.Param("id")
 	venueID, _ := strconv.ParseInt(venueIDStr, 10, 64)
 
-	// 🔒 SECURITY CHECK
+	// Access is checked by the route policy (policy.VenueEdit)
 	userID := c.MustGet("userID").(int64)
 	userRole := c.MustGet("userRole").(string)
 
-	if userRole != "admin" {
-		if err := VerifyVenueOwnership(venueID, userID); err != nil {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-			return
-		}
-	}
-
 	// UPLOAD LOGIC
 	file, err := c.FormFile("image")
 	if err != nil {
@@ -148,35 +288,28 @@ func UploadVenuePhotoHandler(c *gin.Context) {
 		return
 	}
 
-	src, err := file.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open file"})
-		return
-	}
-	defer src.Close()
-
-	ctx := context.Background()
-	params := uploader.UploadParams{
-		Folder: "playarena_venues",
-	}
-
-	result, err := cld.Upload.Upload(ctx, src, params)
+	caption, err := NormalizePhotoCaption(c.PostForm("caption"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	imageURL := result.SecureURL
-
-	_, err = db.DB.Exec("INSERT INTO venue_photos (venue_id, image_url) VALUES (?, ?)", venueID, imageURL)
+	photo, err := AddVenuePhoto(venueID, file, caption)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store image URL"})
+		var invalid *imaging.ValidationError
+		if errors.As(err, &invalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	RecordStaffAction(venueID, userID, userRole, "photo_uploaded", fmt.Sprintf("photo %d", photo.ID))
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Photo uploaded successfully",
-		"url":     imageURL,
+		"url":     photo.ImageURL,
+		"photo":   photo,
 	})
 }
 
@@ -214,32 +347,110 @@ func DeleteVenuePhotoHandler(c *gin.Context) {
 	userID := c.MustGet("userID").(int64)
 	userRole := c.MustGet("userRole").(string)
 
-	// --- SECURITY CHECK ---
-	// If not admin, check ownership
-	if userRole != "admin" {
-		// 1. Find which venue this photo belongs to
-		venueID, err := GetVenueIdFromPhoto(photoID)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Photo not found"})
-			return
-		}
-
-		// 2. Check if the user owns that venue
-		if err := VerifyVenueOwnership(venueID, userID); err != nil {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to delete this photo"})
-			return
-		}
+	// Access is checked by the route policy (policy.PhotoEdit); the venue is
+	// only needed for the staff audit trail
+	venueID, err := GetVenueIdFromPhoto(photoID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Photo not found"})
+		return
 	}
-	// ----------------------
 
 	err = DeleteVenuePhoto(photoID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	RecordStaffAction(venueID, userID, userRole, "photo_deleted", fmt.Sprintf("photo %d", photoID))
 	c.JSON(http.StatusOK, gin.H{"message": "Photo deleted successfully"})
 }
 
+// -------------------------------------------------------
+// PHOTO CAPTION / ORDER / COVER
+// -------------------------------------------------------
+
+// UpdateVenuePhotoHandler handles PATCH /api/v1/photos/:id
+func UpdateVenuePhotoHandler(c *gin.Context) {
+	photoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid photo ID"})
+		return
+	}
+
+	var req UpdatePhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	venueID, err := GetVenueIdFromPhoto(photoID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Photo not found"})
+		return
+	}
+
+	photo, err := UpdateVenuePhotoCaption(photoID, req.Caption)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	RecordStaffAction(venueID, userID, userRole, "photo_updated", fmt.Sprintf("photo %d", photoID))
+	c.JSON(http.StatusOK, photo)
+}
+
+// ReorderVenuePhotosHandler handles PUT /api/v1/venues/:id/photos/order
+func ReorderVenuePhotosHandler(c *gin.Context) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	var req ReorderPhotosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "photo_ids is required"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	photos, err := ReorderVenuePhotos(venueID, req.PhotoIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	RecordStaffAction(venueID, userID, userRole, "photos_reordered", "")
+	c.JSON(http.StatusOK, photos)
+}
+
+// SetCoverPhotoHandler handles PUT /api/v1/venues/:id/cover
+func SetCoverPhotoHandler(c *gin.Context) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	var req SetCoverPhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "photo_id is required"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	if err := SetVenueCoverPhoto(venueID, req.PhotoID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	RecordStaffAction(venueID, userID, userRole, "cover_photo_changed", fmt.Sprintf("photo %d", req.PhotoID))
+	c.JSON(http.StatusOK, gin.H{"message": "Cover photo updated"})
+}
+
 // -------------------------------------------------------
 // OWNER'S VENUE LIST
 // -------------------------------------------------------
@@ -266,6 +477,42 @@ func CreateReviewHandler(c *gin.Context) {
 	}
 	userID := c.MustGet("userID").(int64)
 
+	var req struct {
+		BookingID int64  `json:"booking_id" binding:"required"`
+		Rating    int    `json:"rating" binding:"required"`
+		Comment   string `json:"comment"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rating and booking_id are required"})
+		return
+	}
+
+	review, err := AddReview(venueID, userID, req.BookingID, req.Rating, req.Comment)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == ErrReviewExists {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	message := "Review submitted"
+	if review.ModerationStatus == ReviewHeld {
+		message = "Review submitted and is awaiting moderation"
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": message, "review_id": review.ID, "moderation_status": review.ModerationStatus})
+}
+
+// UpdateReviewHandler handles PATCH /api/v1/reviews/:id (author only)
+func UpdateReviewHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	userID := c.MustGet("userID").(int64)
+
 	var req struct {
 		Rating  int    `json:"rating" binding:"required"`
 		Comment string `json:"comment"`
@@ -275,13 +522,170 @@ func CreateReviewHandler(c *gin.Context) {
 		return
 	}
 
-	err = AddReview(venueID, userID, req.Rating, req.Comment)
+	if err := EditReview(reviewID, userID, req.Rating, req.Comment); err != nil {
+		status := http.StatusBadRequest
+		if err == ErrReviewNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review updated"})
+}
+
+// DeleteReviewHandler handles DELETE /api/v1/reviews/:id (author only)
+func DeleteReviewHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit review"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
 		return
 	}
+	userID := c.MustGet("userID").(int64)
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Review submitted"})
+	if err := RemoveReview(reviewID, userID); err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrReviewNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted"})
+}
+
+// ReportReviewHandler handles POST /api/v1/reviews/:id/report
+func ReportReviewHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	userID := c.MustGet("userID").(int64)
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required"})
+		return
+	}
+
+	if err := ReportReview(reviewID, userID, req.Reason); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Thanks, a moderator will review this report"})
+}
+
+// UploadReviewPhotoHandler handles POST /api/v1/reviews/:id/photos (author only)
+func UploadReviewPhotoHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	userID := c.MustGet("userID").(int64)
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image file is required"})
+		return
+	}
+
+	photo, err := AddReviewPhoto(reviewID, userID, file)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == ErrReviewNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// DeleteReviewPhotoHandler handles DELETE /api/v1/reviews/:id/photos/:photo_id
+func DeleteReviewPhotoHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	photoID, err := strconv.ParseInt(c.Param("photo_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid photo ID"})
+		return
+	}
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	if err := RemoveReviewPhoto(reviewID, photoID, userID, userRole); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Photo deleted successfully"})
+}
+
+// ReplyToReviewHandler handles PUT /api/v1/reviews/:id/reply (venue owner or admin)
+func ReplyToReviewHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	var req struct {
+		Reply string `json:"reply" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reply text is required"})
+		return
+	}
+
+	if err := ReplyToReview(reviewID, userID, userRole, req.Reply); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrReplyReviewNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, ErrEmptyReply):
+			status = http.StatusBadRequest
+		case errors.Is(err, ErrReviewNotPublished):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reply saved"})
+}
+
+// DeleteReviewReplyHandler handles DELETE /api/v1/reviews/:id/reply (venue owner or admin)
+func DeleteReviewReplyHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	if err := RemoveReviewReply(reviewID, userID, userRole); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrReplyReviewNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reply removed"})
 }
 
 func GetReviewsHandler(c *gin.Context) {
@@ -309,28 +713,415 @@ func UpdateVenueHandler(c *gin.Context) {
 		return
 	}
 
-	// 🔒 SECURITY CHECK
+	// Access is checked by the route policy (policy.VenueEdit)
 	userID := c.MustGet("userID").(int64)
 	userRole := c.MustGet("userRole").(string)
 
-	if userRole != "admin" {
-		if err := VerifyVenueOwnership(venueID, userID); err != nil {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	var venue Venue
+	if err := c.ShouldBindJSON(&venue); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
+	version, ok := expectedVersion(c, venue.Version)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+		return
+	}
+
+	revision, err := ModifyVenue(venueID, &venue, version, userID, userRole)
+	if err != nil {
+		if errors.Is(err, ErrVenueNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrVersionConflict) || errors.Is(err, ErrVenueArchived) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, ErrVersionRequired) {
+			c.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	var venue Venue
-	if err := c.ShouldBindJSON(&venue); err != nil {
+	RecordStaffAction(venueID, userID, userRole, "venue_updated", "")
+	c.Header("ETag", venueETag(venue.Version))
+	if revision != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message":          "Venue updated. Changes to name, address, location, sports or price will go live once an admin approves them.",
+			"pending_revision": revision,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Venue updated successfully"})
+}
+
+// PatchVenueHandler handles PATCH /api/v1/venues/:id.
+// Only the fields in the body change. The venue's ETag must be sent in
+// If-Match (or its "version" in the body): a newer edit gets 409 Conflict,
+// a missing version 428 Precondition Required.
+func PatchVenueHandler(c *gin.Context) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+
+	var patch VenuePatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
 		return
 	}
 
-	err = ModifyVenue(venueID, &venue)
+	bodyVersion := 0
+	if patch.Version != nil {
+		bodyVersion = *patch.Version
+	}
+	version, ok := expectedVersion(c, bodyVersion)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+		return
+	}
+
+	venue, revision, err := PatchVenue(venueID, &patch, version, userID, userRole)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update venue"})
+		switch {
+		case errors.Is(err, ErrVenueNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrVersionConflict), errors.Is(err, ErrVenueArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrVersionRequired):
+			c.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Venue updated successfully"})
+	RecordStaffAction(venueID, userID, userRole, "venue_updated", "")
+	c.Header("ETag", venueETag(venue.Version))
+	c.JSON(http.StatusOK, gin.H{"venue": venue, "pending_revision": revision})
+}
+
+// UpdateVenueLifecycleHandler handles PUT /api/v1/venues/:id/lifecycle.
+// Owners take a venue offline, bring it back or archive it.
+func UpdateVenueLifecycleHandler(c *gin.Context) {
+	var req LifecycleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+	changeLifecycle(c, req.Lifecycle, req.FutureBookings)
+}
+
+// ArchiveVenueHandler handles DELETE /api/v1/venues/:id.
+// Venues are never hard-deleted; this archives them. Upcoming bookings block
+// the request unless ?future_bookings=cancel is given.
+func ArchiveVenueHandler(c *gin.Context) {
+	changeLifecycle(c, LifecycleArchived, c.Query("future_bookings"))
+}
+
+func changeLifecycle(c *gin.Context, lifecycle, futureBookings string) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(int64)
+	userRole := c.MustGet("userRole").(string)
+
+	result, err := ChangeVenueLifecycle(venueID, userID, userRole, lifecycle, futureBookings)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrVenueNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrUpcomingBookings):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "upcoming_bookings": result.UpcomingBookings})
+		case errors.Is(err, ErrVenueArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetPendingRevisionHandler handles GET /api/v1/venues/:id/revision (owner or admin)
+func GetPendingRevisionHandler(c *gin.Context) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	revision, err := GetPendingRevision(venueID)
+	if err != nil {
+		if errors.Is(err, ErrRevisionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No pending changes for this venue"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch pending changes"})
+		return
+	}
+	c.JSON(http.StatusOK, revision)
+}
+
+// -------------------------------------------------------
+// VENUE REVISIONS (admin)
+// -------------------------------------------------------
+
+// GetPendingRevisionsHandler handles GET /api/v1/admin/venue-revisions
+func GetPendingRevisionsHandler(c *gin.Context) {
+	revisions, err := GetPendingRevisions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch pending revisions"})
+		return
+	}
+	c.JSON(http.StatusOK, revisions)
+}
+
+// DecideRevisionHandler handles PATCH /api/v1/admin/venue-revisions/:id
+func DecideRevisionHandler(c *gin.Context) {
+	revisionID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision ID"})
+		return
+	}
+
+	var req RevisionDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body, 'status' is required"})
+		return
+	}
+
+	adminID := c.MustGet("userID").(int64)
+	if err := DecideRevision(revisionID, adminID, req.Status, req.Note); err != nil {
+		if errors.Is(err, ErrRevisionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Revision " + req.Status})
+}
+
+
+// -------------------------------------------------------
+// REVIEW MODERATION (admin)
+// -------------------------------------------------------
+
+// GetModerationQueueHandler handles GET /api/v1/admin/reviews/reports
+func GetModerationQueueHandler(c *gin.Context) {
+	items, err := GetModerationQueue()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch moderation queue"})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// ModerateReviewHandler handles PATCH /api/v1/admin/reviews/:id
+// Body: {"action": "hide" | "restore", "note": "..."}
+func ModerateReviewHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	adminID := c.MustGet("userID").(int64)
+
+	var req struct {
+		Action string `json:"action" binding:"required"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request, 'action' is required"})
+		return
+	}
+
+	if err := ModerateReview(reviewID, adminID, req.Action, req.Note); err != nil {
+		status := http.StatusBadRequest
+		if err == ErrReviewNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review updated"})
+}
+
+// AdminDeleteReviewHandler handles DELETE /api/v1/admin/reviews/:id
+func AdminDeleteReviewHandler(c *gin.Context) {
+	reviewID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	if err := AdminDeleteReview(reviewID); err != nil {
+		status := http.StatusInternalServerError
+		if err == ErrReviewNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted"})
+}
+
+// -------------------------------------------------------
+// VENUE STAFF
+// -------------------------------------------------------
+
+// staffRequestIDs parses :id and :user_id. Only the venue's owner (or an
+// admin) gets here; staff cannot manage other staff (policy.VenueManage).
+func staffRequestIDs(c *gin.Context) (venueID, staffUserID int64, ok bool) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return 0, 0, false
+	}
+	if c.Param("user_id") != "" {
+		staffUserID, err = strconv.ParseInt(c.Param("user_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return 0, 0, false
+		}
+	}
+	return venueID, staffUserID, true
+}
+
+// GetVenueStaffHandler handles GET /api/v1/venues/:id/staff (owner or admin)
+func GetVenueStaffHandler(c *gin.Context) {
+	venueID, _, ok := staffRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	staff, err := GetVenueStaff(venueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch staff"})
+		return
+	}
+	c.JSON(http.StatusOK, staff)
+}
+
+// InviteStaffHandler handles POST /api/v1/venues/:id/staff (owner or admin)
+func InviteStaffHandler(c *gin.Context) {
+	venueID, _, ok := staffRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req InviteStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request, 'email' and 'permissions' are required"})
+		return
+	}
+
+	if err := InviteStaff(venueID, c.MustGet("userID").(int64), req.Email, req.Permissions); err != nil {
+		switch {
+		case errors.Is(err, ErrVenueNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, ErrVenueArchived):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Invitation sent successfully"})
+}
+
+// UpdateStaffHandler handles PATCH /api/v1/venues/:id/staff/:user_id (owner or admin)
+func UpdateStaffHandler(c *gin.Context) {
+	venueID, staffUserID, ok := staffRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request, 'permissions' is required"})
+		return
+	}
+
+	if err := UpdateStaffPermissions(venueID, c.MustGet("userID").(int64), staffUserID, req.Permissions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Permissions updated"})
+}
+
+// RemoveStaffHandler handles DELETE /api/v1/venues/:id/staff/:user_id (owner or admin)
+func RemoveStaffHandler(c *gin.Context) {
+	venueID, staffUserID, ok := staffRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := RemoveStaff(venueID, c.MustGet("userID").(int64), staffUserID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Staff member removed"})
+}
+
+// GetStaffActionsHandler handles GET /api/v1/venues/:id/staff/actions (owner or admin)
+func GetStaffActionsHandler(c *gin.Context) {
+	venueID, _, ok := staffRequestIDs(c)
+	if !ok {
+		return
+	}
+
+	actions, err := GetStaffActions(venueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch staff activity"})
+		return
+	}
+	c.JSON(http.StatusOK, actions)
+}
+
+// GetMyStaffVenuesHandler handles GET /api/v1/staff/venues
+func GetMyStaffVenuesHandler(c *gin.Context) {
+	assignments, err := GetStaffAssignments(c.MustGet("userID").(int64))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch your staff venues"})
+		return
+	}
+	c.JSON(http.StatusOK, assignments)
+}
+
+// RespondToStaffInviteHandler handles PATCH /api/v1/staff/venues/:id.
+// The invited user accepts or declines; declining later means leaving.
+func RespondToStaffInviteHandler(c *gin.Context) {
+	venueID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	var req StaffInviteResponse
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request, 'status' is required"})
+		return
+	}
+
+	if err := RespondToStaffInvite(venueID, c.MustGet("userID").(int64), req.Status); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Staff status updated"})
 }
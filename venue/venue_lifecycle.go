@@ -0,0 +1,161 @@
+// venue/venue_lifecycle.go
+package venue
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/notification"
+)
+
+// Venue lifecycle states. These are independent of the approval status: only
+// approved, active venues are listed and bookable. Inactive venues keep their
+// page, bookings and reviews; archived venues are gone from public view.
+const (
+	LifecycleActive   = "active"
+	LifecycleInactive = "inactive"
+	LifecycleArchived = "archived"
+)
+
+// How archiving treats bookings that have not ended yet
+const (
+	FutureBookingsBlock  = "block"  // refuse while paid bookings or holds remain
+	FutureBookingsCancel = "cancel" // cancel them all; paid ones are left due a refund
+)
+
+var (
+	ErrVenueArchived    = errors.New("venue is archived")
+	ErrUpcomingBookings = errors.New("venue has upcoming bookings; cancel them first or archive with future_bookings=cancel")
+)
+
+// isBlock reports whether a booking is an owner/admin block rather than a
+// player's booking (see booking.BlockVenueSlot)
+func (b *upcomingBooking) isBlock() bool {
+	return b.Kind == "block"
+}
+
+// isPaid reports whether canceling the booking leaves the player due a refund
+func (b *upcomingBooking) isPaid() bool {
+	return !b.isBlock() && b.Status == "confirmed" && b.TotalPrice > 0
+}
+
+// ChangeVenueLifecycle moves a venue between active, inactive and archived.
+// Owners can switch between active and inactive and archive their venue;
+// only an admin can bring an archived venue back.
+//
+// Archiving with FutureBookingsBlock fails with ErrUpcomingBookings (and a
+// result counting them) while players still hold bookings. With
+// FutureBookingsCancel those bookings are canceled and the players are
+// notified; paid ones are marked 'refund_due' for support to pay back, since
+// there is no payment provider to refund through. Owner blocks never hold up
+// archiving. The venue row and its bookings are read under lock in the same
+// transaction as the change, so neither a concurrent change nor a booking
+// made meanwhile can slip past the checks.
+func ChangeVenueLifecycle(venueID, actorID int64, actorRole, lifecycle, futureBookings string) (*LifecycleResult, error) {
+	switch lifecycle {
+	case LifecycleActive, LifecycleInactive, LifecycleArchived:
+	default:
+		return nil, errors.New("lifecycle must be 'active', 'inactive' or 'archived'")
+	}
+	if futureBookings == "" {
+		futureBookings = FutureBookingsBlock
+	}
+	if futureBookings != FutureBookingsBlock && futureBookings != FutureBookingsCancel {
+		return nil, errors.New("future_bookings must be 'block' or 'cancel'")
+	}
+
+	v, err := FindVenueByID(venueID)
+	if err != nil {
+		return nil, ErrVenueNotFound
+	}
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Decide on the lifecycle as it is under lock, not as first read
+	current, err := LockVenueLifecycle(tx, venueID)
+	if err == sql.ErrNoRows {
+		return nil, ErrVenueNotFound
+	}
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if current == LifecycleArchived && lifecycle != LifecycleArchived && actorRole != "admin" {
+		return nil, ErrVenueArchived
+	}
+
+	upcoming, err := FindUpcomingBookings(tx, venueID)
+	if err != nil {
+		return nil, errors.New("could not check upcoming bookings")
+	}
+	result := &LifecycleResult{VenueID: venueID, Lifecycle: lifecycle}
+	var affected []upcomingBooking
+	for _, b := range upcoming {
+		if !b.isBlock() {
+			affected = append(affected, b)
+		}
+	}
+	result.UpcomingBookings = len(affected)
+
+	if current == lifecycle {
+		return result, nil
+	}
+	if lifecycle == LifecycleArchived && futureBookings == FutureBookingsBlock && len(affected) > 0 {
+		return result, ErrUpcomingBookings
+	}
+
+	if err := UpdateVenueLifecycle(tx, venueID, lifecycle); err != nil {
+		return nil, errors.New("failed to update venue")
+	}
+	if lifecycle == LifecycleArchived && len(upcoming) > 0 {
+		if err := CancelUpcomingBookings(tx, venueID, "venue archived"); err != nil {
+			return nil, errors.New("failed to cancel upcoming bookings")
+		}
+		for _, b := range upcoming {
+			if b.isPaid() {
+				result.RefundDueBookings++
+			} else {
+				result.CanceledBookings++
+			}
+		}
+		result.UpcomingBookings = 0
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if lifecycle == LifecycleArchived {
+		// A staged edit can never go live now
+		_ = DeletePendingRevision(nil, venueID)
+
+		for _, b := range affected {
+			msg := "Your booking at " + v.Name + " on " + b.StartTime.In(VenueLocation).Format("Jan 2, 15:04") +
+				" was canceled because the venue has closed."
+			if b.isPaid() {
+				msg += " You're owed a full refund; our support team will be in touch to arrange it."
+			}
+			_ = notification.CreateNotification(b.UserID, msg, "error")
+		}
+	}
+	if actorRole == "admin" && actorID != v.OwnerID {
+		_ = notification.CreateNotification(v.OwnerID, lifecycleMessage(v.Name, lifecycle), "info")
+	}
+	return result, nil
+}
+
+func lifecycleMessage(venueName, lifecycle string) string {
+	switch lifecycle {
+	case LifecycleActive:
+		return "Your venue " + venueName + " is active again and open for bookings."
+	case LifecycleInactive:
+		return "Your venue " + venueName + " was taken offline by an admin."
+	default:
+		return "Your venue " + venueName + " was archived by an admin."
+	}
+}
@@ -0,0 +1,62 @@
+// venue/venue_media.go
+package venue
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"mime/multipart"
+
+	"github.com/JkD004/playarena-backend/imaging"
+	"github.com/JkD004/playarena-backend/storage"
+)
+
+// MaxVenuePhotoBytes caps the size of an uploaded venue photo
+const MaxVenuePhotoBytes = 10 << 20 // 10 MB
+
+// venuePhotoVariants are the sizes every venue photo is re-encoded into
+var venuePhotoVariants = []imaging.Variant{imaging.Full, imaging.Card, imaging.Thumbnail}
+
+// processUpload validates an uploaded image, renders the requested variants
+// and stores them. On failure nothing is left behind in storage.
+func processUpload(file *multipart.FileHeader, maxBytes int64, folder string, variants ...imaging.Variant) ([]imaging.Encoded, []*storage.Object, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, nil, errors.New("failed to open file")
+	}
+	defer src.Close()
+
+	img, err := imaging.Decode(src, maxBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := imaging.Render(img, variants...)
+	if err != nil {
+		log.Println("Error rendering image variants:", err)
+		return nil, nil, errors.New("failed to process image")
+	}
+
+	objects := make([]*storage.Object, 0, len(encoded))
+	for _, e := range encoded {
+		obj, err := store.Put(context.Background(), folder, bytes.NewReader(e.Data), imaging.ContentType)
+		if err != nil {
+			log.Printf("Error uploading %s variant: %v", e.Variant.Name, err)
+			keys := make([]string, len(objects))
+			for i, o := range objects {
+				keys[i] = o.Key
+			}
+			deleteStoredFiles(keys...)
+			return nil, nil, errors.New("failed to upload image")
+		}
+		objects = append(objects, obj)
+	}
+	return encoded, objects, nil
+}
+
+// deleteStoredFiles removes files from storage in the background; failed
+// deletes are kept and retried (see storage.DeleteLater)
+func deleteStoredFiles(keys ...string) {
+	storage.DeleteLater(store, keys...)
+}
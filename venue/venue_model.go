@@ -7,8 +7,10 @@ type Venue struct {
 	ID            int64     `json:"id"`
 	OwnerID       int64     `json:"owner_id"`
 	Status        string    `json:"status"`
+	StatusNote    string    `json:"status_note,omitempty"` // admin's note on the last decision
 	Name          string    `json:"name"`
-	SportCategory string    `json:"sport_category"`
+	SportCategory string    `json:"sport_category"` // name of the primary sport, kept in step with Sports
+	Sports        []string  `json:"sports,omitempty"` // sport slugs, primary first
 	Description   string    `json:"description,omitempty"`
 	Address       string    `json:"address,omitempty"`
 	PricePerHour  float64   `json:"price_per_hour,omitempty"`
@@ -16,25 +18,348 @@ type Venue struct {
 	ClosingTime   string    `json:"closing_time"`
 	LunchStart    string    `json:"lunch_start_time,omitempty"`
 	LunchEnd      string    `json:"lunch_end_time,omitempty"`
+	Latitude      *float64  `json:"latitude,omitempty"`
+	Longitude     *float64  `json:"longitude,omitempty"`
+	Amenities     []string  `json:"amenities,omitempty"` // amenity slugs
 	CreatedAt     time.Time `json:"created_at"`
+
+	// Version is bumped on every edit; it doubles as the venue's ETag
+	Version int `json:"version"`
+
+	// Lifecycle is active, inactive or archived, independent of Status
+	Lifecycle string `json:"lifecycle"`
+
+	// Review aggregates, maintained by RefreshVenueRating (read-only for clients)
+	AverageRating   float64     `json:"average_rating"`
+	ReviewCount     int         `json:"review_count"`
+	RatingBreakdown map[int]int `json:"rating_breakdown"` // stars (1-5) -> number of reviews
+
+	// DistanceKm is only set on "near" searches, rounded for display;
+	// distanceKm is the exact value the search sorted by
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+	distanceKm float64
+
+	// CoverPhoto is the photo shown on venue cards (nil when there are no photos)
+	CoverPhoto *VenuePhoto `json:"cover_photo,omitempty"`
 }
-// VenuePhoto defines the data structure for a photo
-type VenuePhoto struct {
-	ID        int64     `json:"id"`
-	VenueID   int64     `json:"venue_id"`
-	ImageURL  string    `json:"image_url"`
-	CreatedAt time.Time `json:"created_at"`
+
+// VenuePatch is the body of PATCH /venues/:id. Only the fields that are
+// sent are changed; nil means "leave as is".
+type VenuePatch struct {
+	Name         *string  `json:"name"`
+	Sports       []string `json:"sports"` // replaces the list, primary first
+	Description  *string  `json:"description"`
+	Address      *string  `json:"address"`
+	PricePerHour *float64 `json:"price_per_hour"`
+	OpeningTime  *string  `json:"opening_time"`
+	ClosingTime  *string  `json:"closing_time"`
+	LunchStart   *string  `json:"lunch_start_time"` // "" removes the lunch break
+	LunchEnd     *string  `json:"lunch_end_time"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	Amenities    []string `json:"amenities"` // replaces the list
+
+	// Version is the version the client last saw (an If-Match header takes precedence)
+	Version *int `json:"version"`
 }
 
+// LifecycleRequest is the body of PUT /venues/:id/lifecycle
+type LifecycleRequest struct {
+	Lifecycle      string `json:"lifecycle" binding:"required"`
+	FutureBookings string `json:"future_bookings"` // archiving only: "block" (default) or "cancel"
+}
 
+// LifecycleResult reports what a lifecycle change did
+type LifecycleResult struct {
+	VenueID           int64  `json:"venue_id"`
+	Lifecycle         string `json:"lifecycle"`
+	UpcomingBookings  int    `json:"upcoming_bookings"`   // still on the books after the change
+	CanceledBookings  int    `json:"canceled_bookings"`   // unpaid holds and blocks canceled
+	RefundDueBookings int    `json:"refund_due_bookings"` // paid bookings canceled, awaiting a refund
+}
 
-type Review struct {
+// upcomingBooking is a booking that has not finished yet
+type upcomingBooking struct {
+	ID         int64
+	UserID     int64
+	StartTime  time.Time
+	TotalPrice float64
+	Status     string
+	Kind       string
+}
+
+// StaffMember is a user with delegated access to a venue
+type StaffMember struct {
+	VenueID     int64      `json:"venue_id"`
+	UserID      int64      `json:"user_id"`
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	Email       string     `json:"email"`
+	Permissions []string   `json:"permissions"`
+	Status      string     `json:"status"` // pending until the invite is accepted
+	InvitedBy   int64      `json:"invited_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+}
+
+// StaffAssignment is a venue the current user has been given staff access to
+type StaffAssignment struct {
+	VenueID     int64     `json:"venue_id"`
+	VenueName   string    `json:"venue_name"`
+	Permissions []string  `json:"permissions"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// StaffAction is one entry in a venue's staff audit trail
+type StaffAction struct {
 	ID        int64     `json:"id"`
 	VenueID   int64     `json:"venue_id"`
 	UserID    int64     `json:"user_id"`
-	UserFirst string    `json:"user_first_name"` // To show who wrote it
-	UserLast  string    `json:"user_last_name"`
-	Rating    int       `json:"rating"`
-	Comment   string    `json:"comment"`
+	UserName  string    `json:"user_name"`
+	Action    string    `json:"action"`
+	Details   string    `json:"details,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
+}
+
+// InviteStaffRequest is the body of POST /venues/:id/staff
+type InviteStaffRequest struct {
+	Email       string   `json:"email" binding:"required"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// UpdateStaffRequest is the body of PATCH /venues/:id/staff/:user_id
+type UpdateStaffRequest struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// StaffInviteResponse is the body of PATCH /staff/venues/:id
+type StaffInviteResponse struct {
+	Status string `json:"status" binding:"required"` // "accepted" or "declined"
+}
+
+// VenueStatusChange is one entry in a venue's approval history
+type VenueStatusChange struct {
+	ID         int64     `json:"id"`
+	VenueID    int64     `json:"venue_id"`
+	ActorID    int64     `json:"actor_id"`
+	ActorName  string    `json:"actor_name"`
+	ActorRole  string    `json:"actor_role"`
+	FromStatus string    `json:"from_status,omitempty"` // empty for the initial submission
+	ToStatus   string    `json:"to_status"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// VenueDecisionRequest is the body of PATCH /admin/venues/:id/status
+type VenueDecisionRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note"`
+}
+
+// ResubmitVenueRequest is the body of POST /venues/:id/resubmit
+type ResubmitVenueRequest struct {
+	Note string `json:"note"`
+}
+
+// VenueRevisionFields holds the proposed values of a staged edit.
+// Only fields that differ from the live venue are set.
+type VenueRevisionFields struct {
+	Name         *string           `json:"name,omitempty"`
+	Address      *string           `json:"address,omitempty"`
+	Location     *RevisionLocation `json:"location,omitempty"`
+	Sports       []string          `json:"sports,omitempty"` // slugs, primary first
+	PricePerHour *float64          `json:"price_per_hour,omitempty"`
+}
+
+// RevisionLocation is a proposed coordinate change (nil values clear them)
+type RevisionLocation struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// FieldChange is one line of a revision diff
+type FieldChange struct {
+	Field    string      `json:"field"`
+	Current  interface{} `json:"current"`
+	Proposed interface{} `json:"proposed"`
+}
+
+// VenueRevision is a staged edit waiting for (or decided by) an admin
+type VenueRevision struct {
+	ID          int64               `json:"id"`
+	VenueID     int64               `json:"venue_id"`
+	VenueName   string              `json:"venue_name"`
+	SubmittedBy int64               `json:"submitted_by"`
+	Status      string              `json:"status"`
+	Changes     VenueRevisionFields `json:"changes"`
+	Diff        []FieldChange       `json:"diff"`
+	ReviewedBy  *int64              `json:"reviewed_by,omitempty"`
+	ReviewNote  string              `json:"review_note,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	ReviewedAt  *time.Time          `json:"reviewed_at,omitempty"`
+}
+
+// RevisionDecisionRequest is the body of PATCH /admin/venue-revisions/:id
+type RevisionDecisionRequest struct {
+	Status string `json:"status" binding:"required"` // approved or rejected
+	Note   string `json:"note"`
+}
+
+// UpdatePhotoRequest is the body of PATCH /photos/:id
+type UpdatePhotoRequest struct {
+	Caption string `json:"caption"`
+}
+
+// ReorderPhotosRequest is the body of PUT /venues/:id/photos/order.
+// It must list every photo of the venue exactly once, in the new order.
+type ReorderPhotosRequest struct {
+	PhotoIDs []int64 `json:"photo_ids" binding:"required"`
+}
+
+// SetCoverPhotoRequest is the body of PUT /venues/:id/cover
+type SetCoverPhotoRequest struct {
+	PhotoID int64 `json:"photo_id" binding:"required"`
+}
+
+// Sort keys accepted by GET /venues?sort=
+const (
+	SortNewest    = "newest"
+	SortPrice     = "price"
+	SortPriceDesc = "price_desc"
+	SortRating    = "rating"
+	SortDistance  = "distance"
+)
+
+// VenueSearchParams holds the parsed filters for GET /venues.
+// Nil/empty fields are not applied.
+type VenueSearchParams struct {
+	Sport     string // slug, name or alias; resolved against the sports table
+	MinPrice  *float64
+	MaxPrice  *float64
+	MinRating *float64
+	OpenAt    string   // HH:MM, venue must be open (and not on lunch) at this time
+	Amenities []string // amenity slugs; venue must have all of these
+	Query     string   // free text over name and description
+	Lat, Lng  *float64
+	RadiusKm  float64
+	Sort      string
+	Cursor    string
+	Limit     int
+
+	// Available restricts results to venues bookable for the whole window
+	Available *AvailabilityWindow
+}
+
+// AvailabilityWindow is a requested booking window for availability search
+type AvailabilityWindow struct {
+	Start time.Time
+	End   time.Time
+
+	// Pending bookings created after HoldCutoff still hold their slot
+	HoldCutoff time.Time
+
+	// Local (venue timezone) times of day, set by SearchVenues
+	startTOD, endTOD string
+}
+
+// venueCursor is the keyset position encoded in an opaque cursor string.
+// Sort records the order it was issued for; it is meaningless in any other.
+type venueCursor struct {
+	Sort  string  `json:"s"`
+	Value float64 `json:"v"`
+	ID    int64   `json:"id"`
+}
+
+// VenuePhoto defines the data structure for a photo.
+// ImageURL is the full-size variant; Width/Height are its dimensions.
+type VenuePhoto struct {
+	ID           int64     `json:"id"`
+	VenueID      int64     `json:"venue_id"`
+	Position     int       `json:"position"`
+	Caption      string    `json:"caption"`
+	IsCover      bool      `json:"is_cover"`
+	ImageURL     string    `json:"image_url"`
+	CardURL      string    `json:"card_url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Storage keys of each variant; empty for photos uploaded before the storage package
+	StorageKey   string `json:"-"`
+	CardKey      string `json:"-"`
+	ThumbnailKey string `json:"-"`
+}
+
+// storageKeys lists every stored file that belongs to the photo
+func (p *VenuePhoto) storageKeys() []string {
+	var keys []string
+	for _, k := range []string{p.StorageKey, p.CardKey, p.ThumbnailKey} {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+
+
+type Review struct {
+	ID        int64        `json:"id"`
+	VenueID   int64        `json:"venue_id"`
+	UserID    int64        `json:"user_id"`
+	BookingID *int64       `json:"booking_id,omitempty"`
+	UserFirst string       `json:"user_first_name"` // To show who wrote it
+	UserLast  string       `json:"user_last_name"`
+	Rating    int          `json:"rating"`
+	Comment   string       `json:"comment"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt *time.Time   `json:"updated_at,omitempty"`
+	Reply     *ReviewReply `json:"owner_reply,omitempty"`
+
+	ModerationStatus string `json:"moderation_status"` // 'published', 'held', 'hidden'
+	ModerationNote   string `json:"moderation_note,omitempty"`
+
+	Photos []ReviewPhoto `json:"photos"`
+}
+
+// ReviewPhoto is a picture attached to a review
+type ReviewPhoto struct {
+	ID         int64     `json:"id"`
+	ReviewID   int64     `json:"review_id"`
+	ImageURL   string    `json:"image_url"`
+	StorageKey string    `json:"-"` // storage key, used to delete the asset
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReviewReport is a user's abuse report against a review
+type ReviewReport struct {
+	ID         int64     `json:"id"`
+	ReviewID   int64     `json:"review_id"`
+	ReporterID int64     `json:"reporter_id"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ModerationItem is one entry in the admin moderation queue
+type ModerationItem struct {
+	Review  Review         `json:"review"`
+	Reports []ReviewReport `json:"open_reports"`
+}
+
+// ReviewReply is the venue owner's single public reply to a review
+type ReviewReply struct {
+	Text      string    `json:"text"`
+	RepliedBy int64     `json:"replied_by"`
+	RepliedAt time.Time `json:"replied_at"`
+}
+
+// reviewableBooking is the booking info needed to verify a review
+type reviewableBooking struct {
+	UserID  int64
+	VenueID int64
+	Status  string
+	Kind    string
+	EndTime time.Time
 }
\ No newline at end of file
@@ -0,0 +1,90 @@
+// venue/venue_moderation.go
+package venue
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Review moderation states
+const (
+	ReviewPublished = "published"
+	ReviewHeld      = "held"   // auto-held, waiting for an admin
+	ReviewHidden    = "hidden" // hidden by an admin
+)
+
+// defaultMaxReviewsPerDay applies when REVIEW_MAX_PER_DAY is not set
+const defaultMaxReviewsPerDay = 5
+
+// bannedWords reads the comma separated REVIEW_BANNED_WORDS list.
+// Entries may be single words or multi-word phrases.
+func bannedWords() []string {
+	raw := os.Getenv("REVIEW_BANNED_WORDS")
+	if raw == "" {
+		return nil
+	}
+
+	var words []string
+	for _, w := range strings.Split(raw, ",") {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// maxReviewsPerDay reads REVIEW_MAX_PER_DAY (0 disables the limit)
+func maxReviewsPerDay() int {
+	n, err := strconv.Atoi(os.Getenv("REVIEW_MAX_PER_DAY"))
+	if err != nil || n < 0 {
+		return defaultMaxReviewsPerDay
+	}
+	return n
+}
+
+// containsBannedWord matches whole words (so "class" doesn't trip "ass")
+// and phrases as plain substrings.
+func containsBannedWord(text string, banned []string) (string, bool) {
+	lower := strings.ToLower(text)
+	tokens := make(map[string]bool)
+	for _, t := range strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		tokens[t] = true
+	}
+
+	for _, w := range banned {
+		if strings.Contains(w, " ") {
+			if strings.Contains(lower, w) {
+				return w, true
+			}
+		} else if tokens[w] {
+			return w, true
+		}
+	}
+	return "", false
+}
+
+// autoModerate decides whether a new or edited review is published
+// straight away or held for an admin, and why. For edits it only matters
+// when the review is currently published (see UpdateReview); holds are
+// lifted by a moderator, never by an edit.
+func autoModerate(userID int64, comment string, isNew bool) (string, string) {
+	if _, found := containsBannedWord(comment, bannedWords()); found {
+		return ReviewHeld, "contains a banned word"
+	}
+
+	if isNew {
+		if limit := maxReviewsPerDay(); limit > 0 {
+			count, err := CountReviewsByUserSince(userID, time.Now().Add(-24*time.Hour))
+			if err == nil && count >= limit {
+				return ReviewHeld, "review rate limit exceeded"
+			}
+		}
+	}
+	return ReviewPublished, ""
+}
@@ -3,26 +3,43 @@ package venue
 
 import (
 	"database/sql"
+	"encoding/json"
 	"log"
+	"math"
+	"strings"
+	"time"
 	"github.com/JkD004/playarena-backend/db"
 	"errors"
 )
 
+// venueColumns is the column list scanVenue expects, in order
+const venueColumns = `id, owner_id, status, name, sport_category, description, address, price_per_hour,
+		       opening_time, closing_time, lunch_start_time, lunch_end_time, latitude, longitude, created_at,
+		       rating_avg, rating_count, rating_1, rating_2, rating_3, rating_4, rating_5, status_note, version, lifecycle`
+
 // CreateVenue inserts a new venue into the database
-func CreateVenue(venue *Venue) error {
+func CreateVenue(tx *sql.Tx, venue *Venue) error {
 	query := `
-		INSERT INTO venues (owner_id, name, sport_category, description, address, price_per_hour, opening_time, closing_time, lunch_start_time, lunch_end_time, status)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'pending')
+		INSERT INTO venues (owner_id, name, sport_category, description, address, price_per_hour, opening_time, closing_time, lunch_start_time, lunch_end_time, latitude, longitude, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'pending')
 	`
 	// Handle nullable lunch times
 	var lunchStart, lunchEnd sql.NullString
 	if venue.LunchStart != "" { lunchStart = sql.NullString{String: venue.LunchStart, Valid: true} }
 	if venue.LunchEnd != "" { lunchEnd = sql.NullString{String: venue.LunchEnd, Valid: true} }
 
-	result, err := db.DB.Exec(query, 
+	args := []interface{}{
 		venue.OwnerID, venue.Name, venue.SportCategory, venue.Description, venue.Address, venue.PricePerHour,
 		venue.OpeningTime, venue.ClosingTime, lunchStart, lunchEnd,
-	)
+		venue.Latitude, venue.Longitude,
+	}
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = db.DB.Exec(query, args...)
+	}
 
 	if err != nil {
 		log.Println("Error inserting venue:", err)
@@ -50,8 +67,7 @@ func GetVenueOwner(tx *sql.Tx, venueID int64) (int64, error) {
 // FindVenuesByStatus fetches all venues with a specific status
 func FindVenuesByStatus(status string) ([]Venue, error) {
 	query := `
-		SELECT id, owner_id, status, name, sport_category, description, address, price_per_hour,
-		       opening_time, closing_time, lunch_start_time, lunch_end_time, created_at
+		SELECT ` + venueColumns + `
 		FROM venues WHERE status = ?
 	`
 	rows, err := db.DB.Query(query, status)
@@ -67,25 +83,83 @@ func FindVenuesByStatus(status string) ([]Venue, error) {
 }
 
 
-// UpdateVenueStatusInDB updates the status of a specific venue by its ID
-// UpdateVenueStatusInDB updates the status using a transaction
-func UpdateVenueStatusInDB(tx *sql.Tx, venueID int64, newStatus string) error {
-	query := "UPDATE venues SET status = ? WHERE id = ?"
-	
-	_, err := tx.Exec(query, newStatus, venueID)
+// UpdateVenueStatusInDB updates the status (and the note shown to the
+// owner) using a transaction. The write only happens while the venue is
+// still in fromStatus; it reports false when a concurrent change got there first.
+func UpdateVenueStatusInDB(tx *sql.Tx, venueID int64, fromStatus, newStatus, note string) (bool, error) {
+	query := "UPDATE venues SET status = ?, status_note = NULLIF(?, '') WHERE id = ? AND status = ?"
+
+	result, err := tx.Exec(query, newStatus, note, venueID, fromStatus)
 	if err != nil {
 		log.Println("Error updating venue status:", err)
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// FindVenueByID fetches a venue in any status
+func FindVenueByID(venueID int64) (*Venue, error) {
+	query := `SELECT ` + venueColumns + ` FROM venues WHERE id = ?`
+	rows, err := db.DB.Query(query, venueID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanVenue(rows)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// CreateStatusHistory records a status change in the venue's history
+func CreateStatusHistory(tx *sql.Tx, entry *VenueStatusChange) error {
+	query := `
+		INSERT INTO venue_status_history (venue_id, actor_id, actor_role, from_status, to_status, note)
+		VALUES (?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''))`
+	_, err := tx.Exec(query, entry.VenueID, entry.ActorID, entry.ActorRole, entry.FromStatus, entry.ToStatus, entry.Note)
+	if err != nil {
+		log.Println("Error recording venue status history:", err)
 		return err
 	}
 	return nil
 }
 
+// FindStatusHistory lists a venue's status changes, oldest first
+func FindStatusHistory(venueID int64) ([]VenueStatusChange, error) {
+	query := `
+		SELECT h.id, h.venue_id, h.actor_id, CONCAT(u.first_name, ' ', u.last_name), h.actor_role,
+		       COALESCE(h.from_status, ''), h.to_status, COALESCE(h.note, ''), h.created_at
+		FROM venue_status_history h
+		JOIN users u ON u.id = h.actor_id
+		WHERE h.venue_id = ?
+		ORDER BY h.created_at, h.id`
+	rows, err := db.DB.Query(query, venueID)
+	if err != nil {
+		log.Println("Error querying venue status history:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]VenueStatusChange, 0)
+	for rows.Next() {
+		var h VenueStatusChange
+		if err := rows.Scan(&h.ID, &h.VenueID, &h.ActorID, &h.ActorName, &h.ActorRole,
+			&h.FromStatus, &h.ToStatus, &h.Note, &h.CreatedAt); err != nil {
+			log.Println("Error scanning venue status history:", err)
+			continue
+		}
+		history = append(history, h)
+	}
+	return history, nil
+}
+
 func FindApprovedVenueByID(venueID int64) (*Venue, error) {
 	query := `
-		SELECT id, owner_id, status, name, sport_category, description, address, price_per_hour,
-		       opening_time, closing_time, lunch_start_time, lunch_end_time, created_at
+		SELECT ` + venueColumns + `
 		FROM venues 
-		WHERE id = ? AND status = 'approved'
+		WHERE id = ? AND status = 'approved' AND lifecycle <> 'archived'
 	`
 	rows, err := db.DB.Query(query, venueID)
 	if err != nil { return nil, err }
@@ -100,9 +174,30 @@ func FindApprovedVenues() ([]Venue, error) {
 	return FindVenuesByStatus("approved")
 }
 
-// GetPhotosByVenueID fetches all photos for a specific venue
+// venuePhotoColumns is shared by the venue photo queries (see scanVenuePhoto).
+// Photos from before variants existed fall back to the original image.
+const venuePhotoColumns = `
+	id, venue_id, position, COALESCE(caption, ''),
+	COALESCE(id = (SELECT cover_photo_id FROM venues WHERE venues.id = venue_photos.venue_id), FALSE),
+	image_url, COALESCE(card_url, image_url), COALESCE(thumbnail_url, image_url),
+	COALESCE(width, 0), COALESCE(height, 0), created_at,
+	COALESCE(storage_key, ''), COALESCE(card_key, ''), COALESCE(thumbnail_key, '')`
+
+func scanVenuePhoto(row interface{ Scan(...interface{}) error }) (*VenuePhoto, error) {
+	var p VenuePhoto
+	err := row.Scan(&p.ID, &p.VenueID, &p.Position, &p.Caption, &p.IsCover,
+		&p.ImageURL, &p.CardURL, &p.ThumbnailURL,
+		&p.Width, &p.Height, &p.CreatedAt,
+		&p.StorageKey, &p.CardKey, &p.ThumbnailKey)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetPhotosByVenueID fetches all photos for a specific venue, in gallery order
 func GetPhotosByVenueID(venueID int64) ([]VenuePhoto, error) {
-	query := `SELECT id, venue_id, image_url, created_at FROM venue_photos WHERE venue_id = ?`
+	query := `SELECT ` + venuePhotoColumns + ` FROM venue_photos WHERE venue_id = ? ORDER BY position, id`
 	
 	rows, err := db.DB.Query(query, venueID)
 	if err != nil {
@@ -113,12 +208,12 @@ func GetPhotosByVenueID(venueID int64) ([]VenuePhoto, error) {
 
 	var photos []VenuePhoto
 	for rows.Next() {
-		var photo VenuePhoto
-		if err := rows.Scan(&photo.ID, &photo.VenueID, &photo.ImageURL, &photo.CreatedAt); err != nil {
+		photo, err := scanVenuePhoto(rows)
+		if err != nil {
 			log.Println("Error scanning venue photo:", err)
 			continue
 		}
-		photos = append(photos, photo)
+		photos = append(photos, *photo)
 	}
 
 	if photos == nil {
@@ -127,8 +222,7 @@ func GetPhotosByVenueID(venueID int64) ([]VenuePhoto, error) {
 	return photos, nil
 }
 
-// DeletePhoto deletes a photo by its ID
-// TODO: Add check to ensure user owns this photo/venue
+// DeletePhoto deletes a photo by its ID (ownership is checked by the handler)
 func DeletePhoto(photoID int64) error {
 	query := `DELETE FROM venue_photos WHERE id = ?`
 	_, err := db.DB.Exec(query, photoID)
@@ -139,11 +233,137 @@ func DeletePhoto(photoID int64) error {
 	return nil
 }
 
+// CreateVenuePhoto saves an uploaded venue photo
+func CreateVenuePhoto(photo *VenuePhoto) error {
+	query := `
+		INSERT INTO venue_photos
+			(venue_id, position, caption, image_url, storage_key, card_url, card_key, thumbnail_url, thumbnail_key, width, height)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.DB.Exec(query, photo.VenueID, photo.Position, photo.Caption,
+		photo.ImageURL, photo.StorageKey,
+		photo.CardURL, photo.CardKey,
+		photo.ThumbnailURL, photo.ThumbnailKey,
+		photo.Width, photo.Height)
+	if err != nil {
+		log.Println("Error creating venue photo:", err)
+		return err
+	}
+	photo.ID, _ = result.LastInsertId()
+	photo.CreatedAt = time.Now()
+	return nil
+}
+
+// FindVenuePhotoByID fetches a single venue photo
+func FindVenuePhotoByID(photoID int64) (*VenuePhoto, error) {
+	query := `SELECT ` + venuePhotoColumns + ` FROM venue_photos WHERE id = ?`
+	return scanVenuePhoto(db.DB.QueryRow(query, photoID))
+}
+
+// NextPhotoPosition returns the position after the venue's last photo
+func NextPhotoPosition(venueID int64) (int, error) {
+	var pos int
+	query := `SELECT COALESCE(MAX(position), 0) + 1 FROM venue_photos WHERE venue_id = ?`
+	if err := db.DB.QueryRow(query, venueID).Scan(&pos); err != nil {
+		log.Println("Error finding next photo position:", err)
+		return 0, err
+	}
+	return pos, nil
+}
+
+// UpdatePhotoCaption sets or clears a photo's caption
+func UpdatePhotoCaption(photoID int64, caption string) error {
+	query := `UPDATE venue_photos SET caption = NULLIF(?, '') WHERE id = ?`
+	if _, err := db.DB.Exec(query, caption, photoID); err != nil {
+		log.Println("Error updating photo caption:", err)
+		return err
+	}
+	return nil
+}
+
+// UpdatePhotoPositions stores a new gallery order (photoIDs[0] comes first)
+func UpdatePhotoPositions(venueID int64, photoIDs []int64) error {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE venue_photos SET position = ? WHERE id = ? AND venue_id = ?`
+	for i, id := range photoIDs {
+		if _, err := tx.Exec(query, i+1, id, venueID); err != nil {
+			log.Println("Error updating photo position:", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SetCoverPhoto marks one of the venue's photos as its cover
+func SetCoverPhoto(venueID, photoID int64) error {
+	query := `UPDATE venues SET cover_photo_id = ? WHERE id = ?`
+	if _, err := db.DB.Exec(query, photoID, venueID); err != nil {
+		log.Println("Error setting cover photo:", err)
+		return err
+	}
+	return nil
+}
+
+// RefreshCoverPhoto falls back to the first photo in the gallery when the
+// venue has no cover (first upload, or the cover was deleted)
+func RefreshCoverPhoto(venueID int64) error {
+	query := `
+		UPDATE venues
+		SET cover_photo_id = (
+			SELECT id FROM venue_photos WHERE venue_id = ? ORDER BY position, id LIMIT 1
+		)
+		WHERE id = ? AND cover_photo_id IS NULL`
+	if _, err := db.DB.Exec(query, venueID, venueID); err != nil {
+		log.Println("Error refreshing cover photo:", err)
+		return err
+	}
+	return nil
+}
+
+// FindCoverPhotos returns the cover photo of each venue that has one
+func FindCoverPhotos(venueIDs []int64) (map[int64]*VenuePhoto, error) {
+	result := make(map[int64]*VenuePhoto)
+	if len(venueIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(venueIDs)), ",")
+	args := make([]interface{}, len(venueIDs))
+	for i, id := range venueIDs {
+		args[i] = id
+	}
+
+	query := `
+		SELECT ` + venuePhotoColumns + `
+		FROM venue_photos
+		WHERE id IN (SELECT cover_photo_id FROM venues WHERE id IN (` + placeholders + `))`
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error querying cover photos:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		photo, err := scanVenuePhoto(rows)
+		if err != nil {
+			log.Println("Error scanning cover photo:", err)
+			continue
+		}
+		result[photo.VenueID] = photo
+	}
+	return result, nil
+}
+
 // FindVenuesByOwnerID fetches all venues (any status) for a specific owner
 func FindVenuesByOwnerID(ownerID int64) ([]Venue, error) {
 	query := `
-		SELECT id, owner_id, status, name, sport_category, description, address, price_per_hour,
-		       opening_time, closing_time, lunch_start_time, lunch_end_time, created_at
+		SELECT ` + venueColumns + `
 		FROM venues WHERE owner_id = ?
 	`
 	rows, err := db.DB.Query(query, ownerID)
@@ -162,24 +382,69 @@ func FindVenuesByOwnerID(ownerID int64) ([]Venue, error) {
 // venue/venue_repository.go
 // ... (keep existing functions)
 
-// CreateReview adds a new review
+// CreateReview adds a new review for a booking
 func CreateReview(review *Review) error {
-	query := `INSERT INTO reviews (venue_id, user_id, rating, comment) VALUES (?, ?, ?, ?)`
-	_, err := db.DB.Exec(query, review.VenueID, review.UserID, review.Rating, review.Comment)
+	query := `
+		INSERT INTO reviews (venue_id, user_id, booking_id, rating, comment, moderation_status, moderation_note)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	var note sql.NullString
+	if review.ModerationNote != "" {
+		note = sql.NullString{String: review.ModerationNote, Valid: true}
+	}
+	result, err := db.DB.Exec(query, review.VenueID, review.UserID, review.BookingID, review.Rating, review.Comment, review.ModerationStatus, note)
 	if err != nil {
 		log.Println("Error inserting review:", err)
 		return err
 	}
+
+	id, _ := result.LastInsertId()
+	review.ID = id
 	return nil
 }
 
-// GetReviewsByVenueID fetches all reviews for a venue with user names
+const reviewColumns = `
+	r.id, r.venue_id, r.user_id, r.booking_id, u.first_name, u.last_name, r.rating, r.comment,
+	r.created_at, r.updated_at, r.reply_text, r.reply_by, r.replied_at,
+	r.moderation_status, r.moderation_note
+`
+
+func scanReview(rows *sql.Rows) (*Review, error) {
+	var r Review
+	var bookingID, replyBy sql.NullInt64
+	var updatedAt, repliedAt sql.NullTime
+	var replyText, comment, note sql.NullString
+
+	err := rows.Scan(
+		&r.ID, &r.VenueID, &r.UserID, &bookingID, &r.UserFirst, &r.UserLast, &r.Rating, &comment,
+		&r.CreatedAt, &updatedAt, &replyText, &replyBy, &repliedAt,
+		&r.ModerationStatus, &note,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Comment = comment.String
+	r.ModerationNote = note.String
+	if bookingID.Valid {
+		r.BookingID = &bookingID.Int64
+	}
+	if updatedAt.Valid {
+		r.UpdatedAt = &updatedAt.Time
+	}
+	if replyText.Valid {
+		r.Reply = &ReviewReply{Text: replyText.String, RepliedBy: replyBy.Int64, RepliedAt: repliedAt.Time}
+	}
+	return &r, nil
+}
+
+// GetReviewsByVenueID fetches the published reviews for a venue with user names and owner replies
 func GetReviewsByVenueID(venueID int64) ([]Review, error) {
 	query := `
-		SELECT r.id, r.venue_id, r.user_id, u.first_name, u.last_name, r.rating, r.comment, r.created_at
+		SELECT ` + reviewColumns + `
 		FROM reviews r
 		JOIN users u ON r.user_id = u.id
-		WHERE r.venue_id = ?
+		WHERE r.venue_id = ? AND r.moderation_status = 'published'
 		ORDER BY r.created_at DESC
 	`
 	rows, err := db.DB.Query(query, venueID)
@@ -189,59 +454,194 @@ func GetReviewsByVenueID(venueID int64) ([]Review, error) {
 	}
 	defer rows.Close()
 
-	var reviews []Review
+	reviews := make([]Review, 0)
 	for rows.Next() {
-		var r Review
-		if err := rows.Scan(&r.ID, &r.VenueID, &r.UserID, &r.UserFirst, &r.UserLast, &r.Rating, &r.Comment, &r.CreatedAt); err != nil {
+		r, err := scanReview(rows)
+		if err != nil {
+			log.Println("Error scanning review:", err)
 			continue
 		}
-		reviews = append(reviews, r)
-	}
-	
-	if reviews == nil {
-		reviews = make([]Review, 0)
+		reviews = append(reviews, *r)
 	}
 	return reviews, nil
 }
 
+// FindReviewByID fetches a single review
+func FindReviewByID(reviewID int64) (*Review, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.id = ?
+	`
+	rows, err := db.DB.Query(query, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return scanReview(rows)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// FindReviewableBooking loads the booking fields needed to verify a review
+func FindReviewableBooking(bookingID int64) (*reviewableBooking, error) {
+	var b reviewableBooking
+	query := `SELECT user_id, venue_id, status, kind, end_time FROM bookings WHERE id = ?`
+	err := db.DB.QueryRow(query, bookingID).Scan(&b.UserID, &b.VenueID, &b.Status, &b.Kind, &b.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ReviewExistsForBooking checks if a booking has already been reviewed
+func ReviewExistsForBooking(bookingID int64) (bool, error) {
+	var count int
+	err := db.DB.QueryRow(`SELECT COUNT(*) FROM reviews WHERE booking_id = ?`, bookingID).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateReview changes the rating and comment of a review written by userID.
+// Only a published review takes the new moderation status: an edit can get a
+// review held, but a held or hidden one stays that way (with its original
+// note) until a moderator decides.
+func UpdateReview(reviewID, userID int64, rating int, comment string, status, note string) error {
+	// The note is assigned first: MySQL evaluates SET left to right, so it
+	// must still see the old status
+	query := `
+		UPDATE reviews
+		SET rating = ?, comment = ?,
+		    moderation_note = CASE WHEN moderation_status = 'published' THEN NULLIF(?, '') ELSE moderation_note END,
+		    moderation_status = CASE WHEN moderation_status = 'published' THEN ? ELSE moderation_status END
+		WHERE id = ? AND user_id = ?
+	`
+	result, err := db.DB.Exec(query, rating, comment, note, status, reviewID, userID)
+	if err != nil {
+		log.Println("Error updating review:", err)
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrReviewNotFound
+	}
+	return nil
+}
+
+// DeleteReview removes a review written by userID
+func DeleteReview(reviewID, userID int64) error {
+	query := `DELETE FROM reviews WHERE id = ? AND user_id = ?`
+	result, err := db.DB.Exec(query, reviewID, userID)
+	if err != nil {
+		log.Println("Error deleting review:", err)
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrReviewNotFound
+	}
+	return nil
+}
+
+// SaveReviewReply sets (or replaces) the owner's reply on a published
+// review; it reports false when the review is missing or not published
+func SaveReviewReply(reviewID, ownerID int64, text string) (bool, error) {
+	query := `UPDATE reviews SET reply_text = ?, reply_by = ?, replied_at = NOW(), updated_at = updated_at
+		WHERE id = ? AND moderation_status = 'published'`
+	result, err := db.DB.Exec(query, text, ownerID, reviewID)
+	if err != nil {
+		log.Println("Error saving review reply:", err)
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteReviewReply removes the owner's reply from a review
+func DeleteReviewReply(reviewID int64) error {
+	query := `UPDATE reviews SET reply_text = NULL, reply_by = NULL, replied_at = NULL, updated_at = updated_at WHERE id = ?`
+	_, err := db.DB.Exec(query, reviewID)
+	if err != nil {
+		log.Println("Error deleting review reply:", err)
+		return err
+	}
+	return nil
+}
 
-// UpdateVenueDetails updates the text fields of a venue
-func UpdateVenueDetails(venue *Venue) error {
+// UpdateVenueDetails updates the text fields of a venue and bumps its version.
+// The write only happens if the stored version still matches venue.Version;
+// it reports false when another edit got there first. tx may be nil.
+func UpdateVenueDetails(tx *sql.Tx, venue *Venue) (bool, error) {
 	query := `
 		UPDATE venues 
 		SET name = ?, sport_category = ?, description = ?, address = ?, price_per_hour = ?,
-		    opening_time = ?, closing_time = ?, lunch_start_time = ?, lunch_end_time = ?
-		WHERE id = ?
+		    opening_time = ?, closing_time = ?, lunch_start_time = ?, lunch_end_time = ?,
+		    latitude = ?, longitude = ?, version = version + 1
+		WHERE id = ? AND version = ?
 	`
 	
 	var lunchStart, lunchEnd sql.NullString
 	if venue.LunchStart != "" { lunchStart = sql.NullString{String: venue.LunchStart, Valid: true} }
 	if venue.LunchEnd != "" { lunchEnd = sql.NullString{String: venue.LunchEnd, Valid: true} }
 
-	_, err := db.DB.Exec(query, 
+	args := []interface{}{
 		venue.Name, venue.SportCategory, venue.Description, venue.Address, venue.PricePerHour,
 		venue.OpeningTime, venue.ClosingTime, lunchStart, lunchEnd,
-		venue.ID,
-	)
+		venue.Latitude, venue.Longitude,
+		venue.ID, venue.Version,
+	}
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, args...)
+	} else {
+		result, err = db.DB.Exec(query, args...)
+	}
 	if err != nil {
 		log.Println("Error updating venue details:", err)
-		return err
+		return false, err
 	}
-	return nil
+	// version always changes, so a matched row always counts as affected
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n == 0 {
+		return false, nil
+	}
+	venue.Version++
+	return true, nil
 }
 
-func scanVenue(rows *sql.Rows) (*Venue, error) {
+// scanVenue scans the venueColumns; any extra destinations are scanned
+// from the columns that follow them.
+func scanVenue(rows *sql.Rows, extra ...interface{}) (*Venue, error) {
 	var v Venue
-	var desc, addr, lStart, lEnd sql.NullString
-	var price sql.NullFloat64
+	var desc, addr, lStart, lEnd, statusNote sql.NullString
+	var price, lat, lng sql.NullFloat64
 	var created sql.NullTime
+	var stars [5]int
 
-	err := rows.Scan(
+	dest := []interface{}{
 		&v.ID, &v.OwnerID, &v.Status, &v.Name, &v.SportCategory, 
 		&desc, &addr, &price,
 		&v.OpeningTime, &v.ClosingTime, &lStart, &lEnd,
+		&lat, &lng,
 		&created,
-	)
+		&v.AverageRating, &v.ReviewCount, &stars[0], &stars[1], &stars[2], &stars[3], &stars[4],
+		&statusNote, &v.Version, &v.Lifecycle,
+	}
+	err := rows.Scan(append(dest, extra...)...)
 	if err != nil { return nil, err }
 
 	v.Description = desc.String
@@ -249,7 +649,16 @@ func scanVenue(rows *sql.Rows) (*Venue, error) {
 	v.PricePerHour = price.Float64
 	v.LunchStart = lStart.String
 	v.LunchEnd = lEnd.String
+	if lat.Valid && lng.Valid {
+		v.Latitude = &lat.Float64
+		v.Longitude = &lng.Float64
+	}
 	if created.Valid { v.CreatedAt = created.Time }
+	v.StatusNote = statusNote.String
+	v.RatingBreakdown = make(map[int]int, 5)
+	for i, n := range stars {
+		v.RatingBreakdown[i+1] = n
+	}
 	
 	return &v, nil
 }
@@ -276,3 +685,961 @@ func GetVenueIDByPhotoID(photoID int64) (int64, error) {
 	}
 	return venueID, nil
 }
+
+// -------------------------------------------------------
+// SEARCH
+// -------------------------------------------------------
+
+// escapeLike escapes LIKE wildcards in user input
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// buildVenueSearchQuery turns search params into SQL plus bind args.
+// Every user-supplied value is passed as a placeholder; only the sort
+// column, chosen from a fixed whitelist, is written into the SQL text.
+// The inner query filters venues; the outer one applies the computed
+// columns (distance, rating), the keyset cursor and the ordering.
+func buildVenueSearchQuery(p *VenueSearchParams, cursor *venueCursor) (string, []interface{}) {
+	var args []interface{}
+
+	distanceCol := "NULL"
+	if p.Lat != nil && p.Lng != nil {
+		distanceCol = haversineSQL
+		args = append(args, *p.Lat, *p.Lng, *p.Lat)
+	}
+
+	inner := `
+		SELECT ` + venueColumns + `,
+		       ` + distanceCol + ` AS distance_km
+		FROM venues v
+		WHERE v.status = 'approved' AND v.lifecycle = 'active'`
+
+	if p.Sport != "" {
+		inner += ` AND v.id IN (
+			SELECT vs.venue_id FROM venue_sports vs JOIN sports s ON s.id = vs.sport_id WHERE s.slug = ?)`
+		args = append(args, p.Sport)
+	}
+	if p.MinPrice != nil {
+		inner += ` AND v.price_per_hour >= ?`
+		args = append(args, *p.MinPrice)
+	}
+	if p.MaxPrice != nil {
+		inner += ` AND v.price_per_hour <= ?`
+		args = append(args, *p.MaxPrice)
+	}
+	if p.OpenAt != "" {
+		// Handles venues that close after midnight (closing < opening)
+		inner += ` AND (
+			(v.opening_time <= v.closing_time AND v.opening_time <= ? AND v.closing_time > ?)
+			OR (v.opening_time > v.closing_time AND (v.opening_time <= ? OR v.closing_time > ?))
+		)
+		AND NOT (v.lunch_start_time IS NOT NULL AND v.lunch_end_time IS NOT NULL
+			AND v.lunch_start_time <= ? AND v.lunch_end_time > ?)`
+		for i := 0; i < 6; i++ {
+			args = append(args, p.OpenAt)
+		}
+	}
+	for _, amenity := range p.Amenities {
+		inner += ` AND v.id IN (
+			SELECT va.venue_id FROM venue_amenities va JOIN amenities a ON a.id = va.amenity_id WHERE a.slug = ?)`
+		args = append(args, amenity)
+	}
+	if p.Query != "" {
+		like := "%" + escapeLike(p.Query) + "%"
+		inner += ` AND (v.name LIKE ? OR v.description LIKE ?)`
+		args = append(args, like, like)
+	}
+	if w := p.Available; w != nil {
+		// The whole window must fall inside operating hours (including
+		// venues that close after midnight) and must not touch lunch.
+		inner += ` AND (
+			(v.opening_time <= v.closing_time AND v.opening_time <= ? AND v.closing_time >= ?)
+			OR (v.opening_time > v.closing_time AND (v.opening_time <= ? OR v.closing_time >= ?))
+		)
+		AND NOT (v.lunch_start_time IS NOT NULL AND v.lunch_end_time IS NOT NULL
+			AND v.lunch_start_time < ? AND v.lunch_end_time > ?)`
+		args = append(args, w.startTOD, w.endTOD, w.startTOD, w.endTOD, w.endTOD, w.startTOD)
+
+		// No overlapping confirmed booking or block, and no live hold
+		inner += ` AND NOT EXISTS (
+			SELECT 1 FROM bookings b
+			WHERE b.venue_id = v.id
+			AND b.start_time < ? AND b.end_time > ?
+			AND (b.status = 'confirmed' OR (b.status = 'pending' AND b.created_at > ?))
+		)`
+		args = append(args, w.End, w.Start, w.HoldCutoff)
+	}
+	if p.Lat != nil && p.Lng != nil {
+		// Index-friendly prefilter; the exact radius check happens outside
+		minLat, maxLat, minLng, maxLng := BoundingBox(*p.Lat, *p.Lng, p.RadiusKm)
+		inner += ` AND v.latitude BETWEEN ? AND ? AND v.longitude BETWEEN ? AND ?`
+		args = append(args, minLat, maxLat, minLng, maxLng)
+	}
+
+	query := `SELECT * FROM (` + inner + `
+	) t WHERE 1 = 1`
+
+	if p.Lat != nil && p.Lng != nil {
+		query += ` AND t.distance_km <= ?`
+		args = append(args, p.RadiusKm)
+	}
+	if p.MinRating != nil {
+		query += ` AND t.rating_avg >= ?`
+		args = append(args, *p.MinRating)
+	}
+
+	// Sort column and direction come from a fixed whitelist
+	var sortCol string
+	desc := false
+	switch p.Sort {
+	case SortPrice:
+		sortCol = "t.price_per_hour"
+	case SortPriceDesc:
+		sortCol, desc = "t.price_per_hour", true
+	case SortRating:
+		sortCol, desc = "t.rating_avg", true
+	case SortDistance:
+		sortCol = "t.distance_km"
+	default: // SortNewest
+		desc = true
+	}
+
+	if cursor != nil {
+		if sortCol == "" {
+			query += ` AND t.id < ?`
+			args = append(args, cursor.ID)
+		} else if desc {
+			query += ` AND (` + sortCol + ` < ? OR (` + sortCol + ` = ? AND t.id > ?))`
+			args = append(args, cursor.Value, cursor.Value, cursor.ID)
+		} else {
+			query += ` AND (` + sortCol + ` > ? OR (` + sortCol + ` = ? AND t.id > ?))`
+			args = append(args, cursor.Value, cursor.Value, cursor.ID)
+		}
+	}
+
+	switch {
+	case sortCol == "":
+		query += ` ORDER BY t.id DESC`
+	case desc:
+		query += ` ORDER BY ` + sortCol + ` DESC, t.id ASC`
+	default:
+		query += ` ORDER BY ` + sortCol + ` ASC, t.id ASC`
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	query += ` LIMIT ?`
+	args = append(args, p.Limit+1)
+
+	return query, args
+}
+
+// SearchApprovedVenues runs a venue search and returns one page of results
+// (plus one extra row when there is a next page)
+func SearchApprovedVenues(p *VenueSearchParams, cursor *venueCursor) ([]Venue, error) {
+	query, args := buildVenueSearchQuery(p, cursor)
+
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error searching venues:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	venues := make([]Venue, 0)
+	for rows.Next() {
+		var distance sql.NullFloat64
+		v, err := scanVenue(rows, &distance)
+		if err != nil {
+			log.Println("Error scanning venue:", err)
+			continue
+		}
+		if distance.Valid {
+			v.distanceKm = distance.Float64
+			d := math.Round(distance.Float64*100) / 100
+			v.DistanceKm = &d
+		}
+		venues = append(venues, *v)
+	}
+	return venues, nil
+}
+
+// RefreshVenueRating recomputes a venue's review aggregates from its reviews.
+// It must be called whenever a review is created, edited or removed.
+func RefreshVenueRating(venueID int64) error {
+	query := `
+		UPDATE venues v
+		LEFT JOIN (
+			SELECT venue_id, COUNT(*) AS c, AVG(rating) AS a,
+			       SUM(rating = 1) AS r1, SUM(rating = 2) AS r2, SUM(rating = 3) AS r3,
+			       SUM(rating = 4) AS r4, SUM(rating = 5) AS r5
+			FROM reviews
+			WHERE venue_id = ? AND moderation_status = 'published'
+			GROUP BY venue_id
+		) s ON s.venue_id = v.id
+		SET v.rating_avg = COALESCE(s.a, 0), v.rating_count = COALESCE(s.c, 0),
+		    v.rating_1 = COALESCE(s.r1, 0), v.rating_2 = COALESCE(s.r2, 0), v.rating_3 = COALESCE(s.r3, 0),
+		    v.rating_4 = COALESCE(s.r4, 0), v.rating_5 = COALESCE(s.r5, 0)
+		WHERE v.id = ?
+	`
+	_, err := db.DB.Exec(query, venueID, venueID)
+	if err != nil {
+		log.Println("Error refreshing venue rating:", err)
+		return err
+	}
+	return nil
+}
+
+// -------------------------------------------------------
+// SPORTS & AMENITIES (links to the taxonomy tables)
+// -------------------------------------------------------
+
+// ReplaceVenueSports sets the sports a venue offers; the first is the primary one
+// It runs in tx, or in a transaction of its own when tx is nil.
+func ReplaceVenueSports(tx *sql.Tx, venueID int64, sportIDs []int64) error {
+	if tx == nil {
+		own, err := db.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer own.Rollback()
+		if err := ReplaceVenueSports(own, venueID, sportIDs); err != nil {
+			return err
+		}
+		return own.Commit()
+	}
+
+	if _, err := tx.Exec(`DELETE FROM venue_sports WHERE venue_id = ?`, venueID); err != nil {
+		log.Println("Error clearing venue sports:", err)
+		return err
+	}
+	for i, id := range sportIDs {
+		query := `INSERT IGNORE INTO venue_sports (venue_id, sport_id, is_primary) VALUES (?, ?, ?)`
+		if _, err := tx.Exec(query, venueID, id, i == 0); err != nil {
+			log.Println("Error inserting venue sport:", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// FindSportsForVenues loads sport slugs (primary first) for many venues, keyed by venue ID
+func FindSportsForVenues(venueIDs []int64) (map[int64][]string, error) {
+	result := make(map[int64][]string)
+	if len(venueIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(venueIDs)), ",")
+	args := make([]interface{}, len(venueIDs))
+	for i, id := range venueIDs {
+		args[i] = id
+	}
+
+	query := `
+		SELECT vs.venue_id, s.slug
+		FROM venue_sports vs
+		JOIN sports s ON s.id = vs.sport_id
+		WHERE vs.venue_id IN (` + placeholders + `)
+		ORDER BY vs.is_primary DESC, s.name`
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error querying venue sports:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var venueID int64
+		var slug string
+		if err := rows.Scan(&venueID, &slug); err != nil {
+			continue
+		}
+		result[venueID] = append(result[venueID], slug)
+	}
+	return result, nil
+}
+
+// ReplaceVenueAmenities sets the full amenity list of a venue
+func ReplaceVenueAmenities(tx *sql.Tx, venueID int64, amenityIDs []int64) error {
+	if tx == nil {
+		own, err := db.DB.Begin()
+		if err != nil {
+			return err
+		}
+		defer own.Rollback()
+		if err := ReplaceVenueAmenities(own, venueID, amenityIDs); err != nil {
+			return err
+		}
+		return own.Commit()
+	}
+
+	if _, err := tx.Exec(`DELETE FROM venue_amenities WHERE venue_id = ?`, venueID); err != nil {
+		log.Println("Error clearing venue amenities:", err)
+		return err
+	}
+	for _, id := range amenityIDs {
+		if _, err := tx.Exec(`INSERT IGNORE INTO venue_amenities (venue_id, amenity_id) VALUES (?, ?)`, venueID, id); err != nil {
+			log.Println("Error inserting venue amenity:", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// FindAmenitiesForVenues loads amenity slugs for many venues, keyed by venue ID
+func FindAmenitiesForVenues(venueIDs []int64) (map[int64][]string, error) {
+	result := make(map[int64][]string)
+	if len(venueIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(venueIDs)), ",")
+	args := make([]interface{}, len(venueIDs))
+	for i, id := range venueIDs {
+		args[i] = id
+	}
+
+	query := `
+		SELECT va.venue_id, a.slug
+		FROM venue_amenities va
+		JOIN amenities a ON a.id = va.amenity_id
+		WHERE va.venue_id IN (` + placeholders + `)
+		ORDER BY a.slug`
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error querying venue amenities:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var venueID int64
+		var slug string
+		if err := rows.Scan(&venueID, &slug); err != nil {
+			continue
+		}
+		result[venueID] = append(result[venueID], slug)
+	}
+	return result, nil
+}
+
+// -------------------------------------------------------
+// REVIEW MODERATION
+// -------------------------------------------------------
+
+// CountReviewsByUserSince counts how many reviews a user wrote after `since`
+func CountReviewsByUserSince(userID int64, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM reviews WHERE user_id = ? AND created_at > ?`
+	err := db.DB.QueryRow(query, userID, since).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateReviewReport records an abuse report (one per user per review)
+func CreateReviewReport(reviewID, reporterID int64, reason string) error {
+	query := `INSERT INTO review_reports (review_id, reporter_id, reason) VALUES (?, ?, ?)`
+	_, err := db.DB.Exec(query, reviewID, reporterID, reason)
+	if err != nil {
+		log.Println("Error inserting review report:", err)
+		return err
+	}
+	return nil
+}
+
+// FindModerationQueue fetches held reviews and reviews with open reports
+func FindModerationQueue() ([]ModerationItem, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.moderation_status = 'held'
+		   OR EXISTS (SELECT 1 FROM review_reports rr WHERE rr.review_id = r.id AND rr.status = 'open')
+		ORDER BY r.created_at ASC
+	`
+	rows, err := db.DB.Query(query)
+	if err != nil {
+		log.Println("Error querying moderation queue:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]ModerationItem, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		r, err := scanReview(rows)
+		if err != nil {
+			log.Println("Error scanning review:", err)
+			continue
+		}
+		index[r.ID] = len(items)
+		items = append(items, ModerationItem{Review: *r, Reports: make([]ReviewReport, 0)})
+	}
+
+	reportRows, err := db.DB.Query(`
+		SELECT id, review_id, reporter_id, reason, created_at
+		FROM review_reports
+		WHERE status = 'open'
+		ORDER BY created_at ASC
+	`)
+	if err != nil {
+		log.Println("Error querying review reports:", err)
+		return nil, err
+	}
+	defer reportRows.Close()
+
+	for reportRows.Next() {
+		var rep ReviewReport
+		if err := reportRows.Scan(&rep.ID, &rep.ReviewID, &rep.ReporterID, &rep.Reason, &rep.CreatedAt); err != nil {
+			continue
+		}
+		if i, ok := index[rep.ReviewID]; ok {
+			items[i].Reports = append(items[i].Reports, rep)
+		}
+	}
+	return items, nil
+}
+
+// SetReviewModerationStatus changes a review's moderation state
+func SetReviewModerationStatus(reviewID int64, status, note string) error {
+	query := `UPDATE reviews SET moderation_status = ?, moderation_note = NULLIF(?, ''), updated_at = updated_at WHERE id = ?`
+	_, err := db.DB.Exec(query, status, note, reviewID)
+	if err != nil {
+		log.Println("Error updating review moderation status:", err)
+		return err
+	}
+	return nil
+}
+
+// ResolveReviewReports closes all open reports on a review
+func ResolveReviewReports(reviewID, adminID int64) error {
+	query := `
+		UPDATE review_reports SET status = 'resolved', resolved_by = ?, resolved_at = NOW()
+		WHERE review_id = ? AND status = 'open'
+	`
+	_, err := db.DB.Exec(query, adminID, reviewID)
+	if err != nil {
+		log.Println("Error resolving review reports:", err)
+		return err
+	}
+	return nil
+}
+
+// DeleteReviewByID removes a review regardless of author (admin only)
+func DeleteReviewByID(reviewID int64) error {
+	_, err := db.DB.Exec(`DELETE FROM reviews WHERE id = ?`, reviewID)
+	if err != nil {
+		log.Println("Error deleting review:", err)
+		return err
+	}
+	return nil
+}
+
+// -------------------------------------------------------
+// REVIEW PHOTOS
+// -------------------------------------------------------
+
+// CreateReviewPhoto stores an uploaded review photo
+func CreateReviewPhoto(tx *sql.Tx, photo *ReviewPhoto) error {
+	query := `INSERT INTO review_photos (review_id, image_url, storage_key) VALUES (?, ?, ?)`
+	result, err := tx.Exec(query, photo.ReviewID, photo.ImageURL, photo.StorageKey)
+	if err != nil {
+		log.Println("Error inserting review photo:", err)
+		return err
+	}
+
+	id, _ := result.LastInsertId()
+	photo.ID = id
+	return nil
+}
+
+// LockReview locks a review row until the transaction ends, so photos are
+// counted and added to it one upload at a time
+func LockReview(tx *sql.Tx, reviewID int64) error {
+	var id int64
+	return tx.QueryRow(`SELECT id FROM reviews WHERE id = ? FOR UPDATE`, reviewID).Scan(&id)
+}
+
+// CountReviewPhotos counts the photos attached to a review
+func CountReviewPhotos(tx *sql.Tx, reviewID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM review_photos WHERE review_id = ?`
+	var count int
+	var err error
+	if tx != nil {
+		err = tx.QueryRow(query, reviewID).Scan(&count)
+	} else {
+		err = db.DB.QueryRow(query, reviewID).Scan(&count)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindReviewPhotos loads the photos of many reviews, keyed by review ID
+func FindReviewPhotos(reviewIDs []int64) (map[int64][]ReviewPhoto, error) {
+	result := make(map[int64][]ReviewPhoto)
+	if len(reviewIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(reviewIDs)), ",")
+	args := make([]interface{}, len(reviewIDs))
+	for i, id := range reviewIDs {
+		args[i] = id
+	}
+
+	query := `
+		SELECT id, review_id, image_url, storage_key, created_at
+		FROM review_photos
+		WHERE review_id IN (` + placeholders + `)
+		ORDER BY id
+	`
+	rows, err := db.DB.Query(query, args...)
+	if err != nil {
+		log.Println("Error querying review photos:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p ReviewPhoto
+		if err := rows.Scan(&p.ID, &p.ReviewID, &p.ImageURL, &p.StorageKey, &p.CreatedAt); err != nil {
+			continue
+		}
+		result[p.ReviewID] = append(result[p.ReviewID], p)
+	}
+	return result, nil
+}
+
+// FindReviewPhotoByID fetches a single review photo
+func FindReviewPhotoByID(photoID int64) (*ReviewPhoto, error) {
+	var p ReviewPhoto
+	query := `SELECT id, review_id, image_url, storage_key, created_at FROM review_photos WHERE id = ?`
+	err := db.DB.QueryRow(query, photoID).Scan(&p.ID, &p.ReviewID, &p.ImageURL, &p.StorageKey, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DeleteReviewPhotoRow removes a review photo record
+func DeleteReviewPhotoRow(photoID int64) error {
+	_, err := db.DB.Exec(`DELETE FROM review_photos WHERE id = ?`, photoID)
+	if err != nil {
+		log.Println("Error deleting review photo:", err)
+		return err
+	}
+	return nil
+}
+
+// -------------------------------------------------------
+// REVISIONS (staged edits to sensitive fields)
+// -------------------------------------------------------
+
+// SavePendingRevision stores the staged changes for a venue, replacing any
+// revision that is still pending, and returns the revision ID
+func SavePendingRevision(tx *sql.Tx, venueID, userID int64, changes *VenueRevisionFields) (int64, error) {
+	raw, err := json.Marshal(changes)
+	if err != nil {
+		return 0, err
+	}
+	if tx == nil {
+		own, err := db.DB.Begin()
+		if err != nil {
+			return 0, err
+		}
+		defer own.Rollback()
+		id, err := SavePendingRevision(own, venueID, userID, changes)
+		if err != nil {
+			return 0, err
+		}
+		return id, own.Commit()
+	}
+
+	var id int64
+	err = tx.QueryRow(`SELECT id FROM venue_revisions WHERE venue_id = ? AND status = 'pending' FOR UPDATE`, venueID).Scan(&id)
+	switch {
+	case err == sql.ErrNoRows:
+		result, err := tx.Exec(`INSERT INTO venue_revisions (venue_id, submitted_by, changes) VALUES (?, ?, ?)`, venueID, userID, raw)
+		if err != nil {
+			log.Println("Error creating venue revision:", err)
+			return 0, err
+		}
+		id, _ = result.LastInsertId()
+		return id, nil
+	case err != nil:
+		log.Println("Error finding pending revision:", err)
+		return 0, err
+	}
+
+	query := `UPDATE venue_revisions SET submitted_by = ?, changes = ?, created_at = CURRENT_TIMESTAMP WHERE id = ?`
+	if _, err := tx.Exec(query, userID, raw, id); err != nil {
+		log.Println("Error updating venue revision:", err)
+		return 0, err
+	}
+	return id, nil
+}
+
+const revisionColumns = `
+	r.id, r.venue_id, v.name, r.submitted_by, r.status, r.changes,
+	r.reviewed_by, COALESCE(r.review_note, ''), r.created_at, r.reviewed_at`
+
+func scanRevision(row interface{ Scan(...interface{}) error }) (*VenueRevision, error) {
+	var r VenueRevision
+	var raw []byte
+	var reviewedBy sql.NullInt64
+	var reviewedAt sql.NullTime
+	err := row.Scan(&r.ID, &r.VenueID, &r.VenueName, &r.SubmittedBy, &r.Status, &raw,
+		&reviewedBy, &r.ReviewNote, &r.CreatedAt, &reviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &r.Changes); err != nil {
+		return nil, err
+	}
+	if reviewedBy.Valid {
+		r.ReviewedBy = &reviewedBy.Int64
+	}
+	if reviewedAt.Valid {
+		r.ReviewedAt = &reviewedAt.Time
+	}
+	return &r, nil
+}
+
+// FindRevisionByID fetches a single revision
+func FindRevisionByID(revisionID int64) (*VenueRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM venue_revisions r JOIN venues v ON v.id = r.venue_id WHERE r.id = ?`
+	return scanRevision(db.DB.QueryRow(query, revisionID))
+}
+
+// FindPendingRevisionForVenue fetches the venue's pending revision, if any
+func FindPendingRevisionForVenue(venueID int64) (*VenueRevision, error) {
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM venue_revisions r JOIN venues v ON v.id = r.venue_id
+		WHERE r.venue_id = ? AND r.status = 'pending'`
+	return scanRevision(db.DB.QueryRow(query, venueID))
+}
+
+// FindPendingRevisions lists all pending revisions, oldest first
+func FindPendingRevisions() ([]VenueRevision, error) {
+	query := `
+		SELECT ` + revisionColumns + `
+		FROM venue_revisions r JOIN venues v ON v.id = r.venue_id
+		WHERE r.status = 'pending'
+		ORDER BY r.created_at`
+	rows, err := db.DB.Query(query)
+	if err != nil {
+		log.Println("Error querying venue revisions:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]VenueRevision, 0)
+	for rows.Next() {
+		r, err := scanRevision(rows)
+		if err != nil {
+			log.Println("Error scanning venue revision:", err)
+			continue
+		}
+		revisions = append(revisions, *r)
+	}
+	return revisions, nil
+}
+
+// ResolveRevision records the admin's decision on a pending revision.
+// Only one decision can claim a revision: ErrRevisionNotFound means it was
+// no longer pending. tx may be nil.
+func ResolveRevision(tx *sql.Tx, revisionID, adminID int64, status, note string) error {
+	query := `
+		UPDATE venue_revisions
+		SET status = ?, reviewed_by = ?, review_note = NULLIF(?, ''), reviewed_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = 'pending'`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.Exec(query, status, adminID, note, revisionID)
+	} else {
+		result, err = db.DB.Exec(query, status, adminID, note, revisionID)
+	}
+	if err != nil {
+		log.Println("Error resolving venue revision:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrRevisionNotFound
+	}
+	return nil
+}
+
+// DeletePendingRevision drops the venue's pending revision (the owner's
+// latest edit no longer changes any sensitive field)
+func DeletePendingRevision(tx *sql.Tx, venueID int64) error {
+	query := `DELETE FROM venue_revisions WHERE venue_id = ? AND status = 'pending'`
+	var err error
+	if tx != nil {
+		_, err = tx.Exec(query, venueID)
+	} else {
+		_, err = db.DB.Exec(query, venueID)
+	}
+	if err != nil {
+		log.Println("Error deleting pending revision:", err)
+		return err
+	}
+	return nil
+}
+
+// FindUpcomingBookings lists the venue's confirmed and pending bookings that
+// have not ended yet (including owner blocks). Inside a transaction the rows
+// are locked, so they can't change before the transaction ends.
+func FindUpcomingBookings(tx *sql.Tx, venueID int64) ([]upcomingBooking, error) {
+	query := `
+		SELECT id, user_id, start_time, total_price, status, kind
+		FROM bookings
+		WHERE venue_id = ? AND end_time > NOW() AND status IN ('confirmed', 'pending')
+		ORDER BY start_time`
+	var rows *sql.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.Query(query+" FOR UPDATE", venueID)
+	} else {
+		rows, err = db.DB.Query(query, venueID)
+	}
+	if err != nil {
+		log.Println("Error querying upcoming bookings:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	bookings := make([]upcomingBooking, 0)
+	for rows.Next() {
+		var b upcomingBooking
+		if err := rows.Scan(&b.ID, &b.UserID, &b.StartTime, &b.TotalPrice, &b.Status, &b.Kind); err != nil {
+			log.Println("Error scanning upcoming booking:", err)
+			return nil, err
+		}
+		bookings = append(bookings, b)
+	}
+	return bookings, nil
+}
+
+// UpdateVenueLifecycle sets the venue's lifecycle state
+// LockVenueLifecycle reads a venue's lifecycle and locks its row until the
+// transaction ends, so concurrent lifecycle changes run one after another
+func LockVenueLifecycle(tx *sql.Tx, venueID int64) (string, error) {
+	var lifecycle string
+	err := tx.QueryRow(`SELECT lifecycle FROM venues WHERE id = ? FOR UPDATE`, venueID).Scan(&lifecycle)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error locking venue lifecycle:", err)
+	}
+	return lifecycle, err
+}
+
+func UpdateVenueLifecycle(tx *sql.Tx, venueID int64, lifecycle string) error {
+	query := `UPDATE venues SET lifecycle = ?, lifecycle_changed_at = CURRENT_TIMESTAMP WHERE id = ?`
+	_, err := tx.Exec(query, lifecycle, venueID)
+	if err != nil {
+		log.Println("Error updating venue lifecycle:", err)
+		return err
+	}
+	return nil
+}
+
+// CancelUpcomingBookings cancels every booking of the venue that has not ended.
+// Paid bookings become 'refund_due' until someone refunds the player; holds
+// and blocks become 'canceled'.
+func CancelUpcomingBookings(tx *sql.Tx, venueID int64, reason string) error {
+	query := `
+		UPDATE bookings
+		SET status = CASE WHEN status = 'confirmed' AND total_price > 0 THEN 'refund_due' ELSE 'canceled' END,
+		    canceled_reason = ?
+		WHERE venue_id = ? AND end_time > NOW() AND status IN ('confirmed', 'pending')`
+	_, err := tx.Exec(query, reason, venueID)
+	if err != nil {
+		log.Println("Error canceling upcoming bookings:", err)
+		return err
+	}
+	return nil
+}
+
+// FindStaffGrant returns a user's staff status and permissions at a venue
+// (sql.ErrNoRows when they have no grant)
+func FindStaffGrant(venueID, userID int64) (string, []string, error) {
+	var status string
+	var raw []byte
+	query := `SELECT status, permissions FROM venue_staff WHERE venue_id = ? AND user_id = ?`
+	err := db.DB.QueryRow(query, venueID, userID).Scan(&status, &raw)
+	if err != nil {
+		return "", nil, err
+	}
+	var perms []string
+	if err := json.Unmarshal(raw, &perms); err != nil {
+		log.Println("Error decoding staff permissions:", err)
+		return "", nil, err
+	}
+	return status, perms, nil
+}
+
+// CreateStaffInvite adds a pending staff grant
+func CreateStaffInvite(venueID, userID, invitedBy int64, perms []string) error {
+	raw, err := json.Marshal(perms)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO venue_staff (venue_id, user_id, permissions, status, invited_by) VALUES (?, ?, ?, 'pending', ?)`
+	if _, err := db.DB.Exec(query, venueID, userID, raw, invitedBy); err != nil {
+		log.Println("Error creating staff invite:", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateStaffPermissionsInDB replaces a staff member's permissions
+func UpdateStaffPermissionsInDB(venueID, userID int64, perms []string) (bool, error) {
+	raw, err := json.Marshal(perms)
+	if err != nil {
+		return false, err
+	}
+	query := `UPDATE venue_staff SET permissions = ? WHERE venue_id = ? AND user_id = ?`
+	result, err := db.DB.Exec(query, raw, venueID, userID)
+	if err != nil {
+		log.Println("Error updating staff permissions:", err)
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// ActivateStaff marks a pending invite as accepted
+func ActivateStaff(venueID, userID int64) (bool, error) {
+	query := `UPDATE venue_staff SET status = 'active', accepted_at = CURRENT_TIMESTAMP WHERE venue_id = ? AND user_id = ? AND status = 'pending'`
+	result, err := db.DB.Exec(query, venueID, userID)
+	if err != nil {
+		log.Println("Error activating staff:", err)
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// DeleteStaff removes a staff grant (revoked, declined or left)
+func DeleteStaff(venueID, userID int64) (bool, error) {
+	result, err := db.DB.Exec(`DELETE FROM venue_staff WHERE venue_id = ? AND user_id = ?`, venueID, userID)
+	if err != nil {
+		log.Println("Error deleting staff:", err)
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// FindStaffForVenue lists everyone with (or invited to) staff access to a venue
+func FindStaffForVenue(venueID int64) ([]StaffMember, error) {
+	query := `
+		SELECT s.venue_id, s.user_id, u.first_name, u.last_name, u.email, s.permissions,
+		       s.status, s.invited_by, s.created_at, s.accepted_at
+		FROM venue_staff s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.venue_id = ?
+		ORDER BY s.created_at`
+	rows, err := db.DB.Query(query, venueID)
+	if err != nil {
+		log.Println("Error querying venue staff:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	staff := make([]StaffMember, 0)
+	for rows.Next() {
+		var m StaffMember
+		var raw []byte
+		var accepted sql.NullTime
+		if err := rows.Scan(&m.VenueID, &m.UserID, &m.FirstName, &m.LastName, &m.Email, &raw,
+			&m.Status, &m.InvitedBy, &m.CreatedAt, &accepted); err != nil {
+			log.Println("Error scanning venue staff:", err)
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &m.Permissions); err != nil {
+			return nil, err
+		}
+		if accepted.Valid {
+			m.AcceptedAt = &accepted.Time
+		}
+		staff = append(staff, m)
+	}
+	return staff, nil
+}
+
+// FindStaffAssignments lists the venues a user is staff at (or invited to)
+func FindStaffAssignments(userID int64) ([]StaffAssignment, error) {
+	query := `
+		SELECT s.venue_id, v.name, s.permissions, s.status, s.created_at
+		FROM venue_staff s
+		JOIN venues v ON v.id = s.venue_id
+		WHERE s.user_id = ? AND v.lifecycle <> 'archived'
+		ORDER BY v.name`
+	rows, err := db.DB.Query(query, userID)
+	if err != nil {
+		log.Println("Error querying staff assignments:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := make([]StaffAssignment, 0)
+	for rows.Next() {
+		var a StaffAssignment
+		var raw []byte
+		if err := rows.Scan(&a.VenueID, &a.VenueName, &raw, &a.Status, &a.CreatedAt); err != nil {
+			log.Println("Error scanning staff assignment:", err)
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &a.Permissions); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, nil
+}
+
+// CreateStaffAction appends an entry to the venue's staff audit trail
+func CreateStaffAction(venueID, userID int64, action, details string) error {
+	query := `INSERT INTO venue_staff_actions (venue_id, user_id, action, details) VALUES (?, ?, ?, NULLIF(?, ''))`
+	if _, err := db.DB.Exec(query, venueID, userID, action, details); err != nil {
+		log.Println("Error recording staff action:", err)
+		return err
+	}
+	return nil
+}
+
+// FindStaffActions lists a venue's staff audit trail, newest first
+func FindStaffActions(venueID int64, limit int) ([]StaffAction, error) {
+	query := `
+		SELECT a.id, a.venue_id, a.user_id, CONCAT(u.first_name, ' ', u.last_name),
+		       a.action, COALESCE(a.details, ''), a.created_at
+		FROM venue_staff_actions a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.venue_id = ?
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT ?`
+	rows, err := db.DB.Query(query, venueID, limit)
+	if err != nil {
+		log.Println("Error querying staff actions:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	actions := make([]StaffAction, 0)
+	for rows.Next() {
+		var a StaffAction
+		if err := rows.Scan(&a.ID, &a.VenueID, &a.UserID, &a.UserName, &a.Action, &a.Details, &a.CreatedAt); err != nil {
+			log.Println("Error scanning staff action:", err)
+			return nil, err
+		}
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
@@ -0,0 +1,264 @@
+// venue/venue_revision.go
+package venue
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/notification"
+	"github.com/JkD004/playarena-backend/taxonomy"
+)
+
+// Revision states
+const (
+	RevisionPending  = "pending"
+	RevisionApproved = "approved"
+	RevisionRejected = "rejected"
+)
+
+// ErrRevisionNotFound is returned when there is no such (pending) revision
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Sensitive fields of an approved venue are name, address/location, sports
+// and price. Owner edits to them are staged for an admin; everything else
+// (description, hours, amenities, ...) applies immediately.
+
+// stageSensitiveChanges compares an owner's edit with the live venue. Changed
+// sensitive fields are returned as a revision and reset on the edit, so the
+// live listing keeps its current values until the revision is approved.
+func stageSensitiveChanges(live, edit *Venue) *VenueRevisionFields {
+	changes := &VenueRevisionFields{}
+
+	if edit.Name != live.Name {
+		name := edit.Name
+		changes.Name = &name
+		edit.Name = live.Name
+	}
+	if edit.Address != live.Address {
+		address := edit.Address
+		changes.Address = &address
+		edit.Address = live.Address
+	}
+	if edit.PricePerHour != live.PricePerHour {
+		price := edit.PricePerHour
+		changes.PricePerHour = &price
+		edit.PricePerHour = live.PricePerHour
+	}
+	if !sameFloat(edit.Latitude, live.Latitude) || !sameFloat(edit.Longitude, live.Longitude) {
+		changes.Location = &RevisionLocation{Latitude: edit.Latitude, Longitude: edit.Longitude}
+		edit.Latitude, edit.Longitude = live.Latitude, live.Longitude
+	}
+	if !sameSports(edit.Sports, live.Sports) {
+		changes.Sports = edit.Sports
+		edit.Sports, edit.SportCategory = live.Sports, live.SportCategory
+	}
+	return changes
+}
+
+// sameSports compares sport lists: same primary (first) sport, same set overall
+func sameSports(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	if len(a) == 0 {
+		return true
+	}
+	if a[0] != b[0] {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if !set[s] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func (f *VenueRevisionFields) isEmpty() bool {
+	return f.Name == nil && f.Address == nil && f.Location == nil && f.Sports == nil && f.PricePerHour == nil
+}
+
+// revisionDiff lists each staged field next to its live value
+func revisionDiff(live *Venue, c *VenueRevisionFields) []FieldChange {
+	diff := make([]FieldChange, 0)
+	if c.Name != nil {
+		diff = append(diff, FieldChange{Field: "name", Current: live.Name, Proposed: *c.Name})
+	}
+	if c.Address != nil {
+		diff = append(diff, FieldChange{Field: "address", Current: live.Address, Proposed: *c.Address})
+	}
+	if c.Location != nil {
+		diff = append(diff, FieldChange{
+			Field:    "location",
+			Current:  RevisionLocation{Latitude: live.Latitude, Longitude: live.Longitude},
+			Proposed: *c.Location,
+		})
+	}
+	if c.Sports != nil {
+		diff = append(diff, FieldChange{Field: "sports", Current: live.Sports, Proposed: c.Sports})
+	}
+	if c.PricePerHour != nil {
+		diff = append(diff, FieldChange{Field: "price_per_hour", Current: live.PricePerHour, Proposed: *c.PricePerHour})
+	}
+	return diff
+}
+
+// loadVenue fetches a venue in any status along with its sports and amenities
+func loadVenue(venueID int64) (*Venue, error) {
+	v, err := FindVenueByID(venueID)
+	if err != nil {
+		return nil, err
+	}
+	venues := []Venue{*v}
+	if err := attachAmenities(venues); err != nil {
+		return nil, err
+	}
+	return &venues[0], nil
+}
+
+// withDiff fills in the revision's diff against the live venue
+func withDiff(r *VenueRevision) (*VenueRevision, error) {
+	live, err := loadVenue(r.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	r.Diff = revisionDiff(live, &r.Changes)
+	return r, nil
+}
+
+// GetPendingRevision returns the venue's pending revision with its diff
+func GetPendingRevision(venueID int64) (*VenueRevision, error) {
+	r, err := FindPendingRevisionForVenue(venueID)
+	if err == sql.ErrNoRows {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withDiff(r)
+}
+
+// GetPendingRevisions is the admin queue of staged edits, with diffs
+func GetPendingRevisions() ([]VenueRevision, error) {
+	revisions, err := FindPendingRevisions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		if _, err := withDiff(&revisions[i]); err != nil {
+			log.Printf("Error building diff for revision %d: %v", revisions[i].ID, err)
+		}
+	}
+	return revisions, nil
+}
+
+// DecideRevision approves (applies) or rejects a staged edit
+func DecideRevision(revisionID, adminID int64, status, note string) error {
+	note = strings.TrimSpace(note)
+
+	r, err := FindRevisionByID(revisionID)
+	if err != nil || r.Status != RevisionPending {
+		return ErrRevisionNotFound
+	}
+	live, err := loadVenue(r.VenueID)
+	if err != nil {
+		return ErrVenueNotFound
+	}
+
+	switch status {
+	case RevisionApproved:
+		// Claim the revision first, then apply it in the same transaction:
+		// a concurrent decision finds it resolved, and a failed apply leaves
+		// it pending with the live venue untouched
+		tx, err := db.DB.Begin()
+		if err != nil {
+			log.Println("Error starting transaction:", err)
+			return errors.New("database error")
+		}
+		defer tx.Rollback()
+
+		if err := ResolveRevision(tx, revisionID, adminID, status, note); err != nil {
+			return err
+		}
+		if err := applyRevision(tx, live, &r.Changes); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return errors.New("failed to apply revision")
+		}
+		msg := "Your changes to " + live.Name + " were approved and are now live."
+		if note != "" {
+			msg += " Note: " + note
+		}
+		_ = notification.CreateNotification(live.OwnerID, msg, "success")
+
+	case RevisionRejected:
+		if note == "" {
+			return errors.New("a note explaining the rejection is required")
+		}
+		if err := ResolveRevision(nil, revisionID, adminID, status, note); err != nil {
+			return err
+		}
+		_ = notification.CreateNotification(live.OwnerID, "Your changes to "+live.Name+" were rejected. Note: "+note, "error")
+
+	default:
+		return errors.New("status must be 'approved' or 'rejected'")
+	}
+	return nil
+}
+
+// applyRevision writes the staged values onto the live venue inside tx
+func applyRevision(tx *sql.Tx, live *Venue, c *VenueRevisionFields) error {
+	if c.Name != nil {
+		live.Name = *c.Name
+	}
+	if c.Address != nil {
+		live.Address = *c.Address
+	}
+	if c.PricePerHour != nil {
+		live.PricePerHour = *c.PricePerHour
+	}
+	if c.Location != nil {
+		live.Latitude, live.Longitude = c.Location.Latitude, c.Location.Longitude
+	}
+
+	var sportIDs []int64
+	if c.Sports != nil {
+		sports, err := taxonomy.ResolveSports(c.Sports)
+		if err != nil {
+			return err
+		}
+		for _, s := range sports {
+			sportIDs = append(sportIDs, s.ID)
+		}
+		live.SportCategory = sports[0].Name
+	}
+
+	updated, err := UpdateVenueDetails(tx, live)
+	if err != nil {
+		return errors.New("failed to apply revision")
+	}
+	if !updated {
+		return ErrVersionConflict
+	}
+	if sportIDs != nil {
+		if err := ReplaceVenueSports(tx, live.ID, sportIDs); err != nil {
+			return errors.New("failed to apply sports")
+		}
+	}
+	return nil
+}
@@ -2,32 +2,123 @@
 package venue
 
 import (
-	"github.com/JkD004/playarena-backend/db"           // <-- Import db
+	"github.com/JkD004/playarena-backend/db"
+	"github.com/JkD004/playarena-backend/imaging"
 	"github.com/JkD004/playarena-backend/notification" // <-- Import user
-	"github.com/JkD004/playarena-backend/user"
-	"log"
+	"github.com/JkD004/playarena-backend/taxonomy"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
 	// ... other imports
 )
 
+// ErrVenueSaveFailed means the database could not store a new venue
+var ErrVenueSaveFailed = errors.New("failed to create venue")
+
 // CreateNewVenue is the business logic for creating a venue.
 // It takes the venue data and the ID of the owner from the token.
 func CreateNewVenue(venue *Venue, ownerID int64) error {
 	// Set the OwnerID on the venue struct
 	venue.OwnerID = ownerID
 
-	// You can add validation logic here later
-	// (e.g., check if name is empty, price is not negative)
-
-	// Call the repository to save to DB
-	err := CreateVenue(venue)
+	if err := validateVenue(venue); err != nil {
+		return err
+	}
+	sportIDs, err := resolveVenueSports(venue)
+	if err != nil {
+		return err
+	}
+	amenityIDs, err := resolveVenueAmenities(venue)
 	if err != nil {
 		return err
 	}
 
+	// The venue, its sports and amenities and its first history entry are
+	// saved together, so a failure leaves nothing behind to retry over
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return ErrVenueSaveFailed
+	}
+	defer tx.Rollback()
+
+	if err := CreateVenue(tx, venue); err != nil {
+		return ErrVenueSaveFailed
+	}
+	if err := ReplaceVenueSports(tx, venue.ID, sportIDs); err != nil {
+		return ErrVenueSaveFailed
+	}
+	if amenityIDs != nil {
+		if err := ReplaceVenueAmenities(tx, venue.ID, amenityIDs); err != nil {
+			return ErrVenueSaveFailed
+		}
+	}
+	if err := recordSubmission(tx, venue); err != nil {
+		return ErrVenueSaveFailed
+	}
+	if err := tx.Commit(); err != nil {
+		return ErrVenueSaveFailed
+	}
 	return nil
 }
 
+// resolveVenueSports checks the venue's sports against the managed list.
+// Clients may send "sports" (slugs/names) or just the legacy "sport_category".
+// The canonical names/slugs are written back onto the venue.
+func resolveVenueSports(venue *Venue) ([]int64, error) {
+	names := venue.Sports
+	if len(names) == 0 && strings.TrimSpace(venue.SportCategory) != "" {
+		names = []string{venue.SportCategory}
+	}
+	if len(names) == 0 {
+		return nil, errors.New("at least one sport is required")
+	}
+
+	sports, err := taxonomy.ResolveSports(names)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(sports))
+	venue.Sports = make([]string, len(sports))
+	for i, s := range sports {
+		ids[i] = s.ID
+		venue.Sports[i] = s.Slug
+	}
+	venue.SportCategory = sports[0].Name
+	return ids, nil
+}
+
+// resolveVenueAmenities checks the venue's amenities against the managed list.
+// It returns nil when the client didn't send the list (leave unchanged).
+func resolveVenueAmenities(venue *Venue) ([]int64, error) {
+	if venue.Amenities == nil {
+		return nil, nil
+	}
+
+	amenities, err := taxonomy.ResolveAmenities(venue.Amenities)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(amenities))
+	venue.Amenities = make([]string, len(amenities))
+	for i, a := range amenities {
+		ids[i] = a.ID
+		venue.Amenities[i] = a.Slug
+	}
+	return ids, nil
+}
+
 // ... We're keeping the old GetAllVenues for now,
 // but you should update it to fetch from the DB later.
 var sampleVenues = []Venue{
@@ -47,123 +138,886 @@ func GetAllVenues() ([]Venue, error) {
 	return venues, nil
 }
 
-// GetVenuesByStatus is the service-layer function to get venues
-func GetVenuesByStatus(status string) ([]Venue, error) {
-	// You could add validation here, e.g., check if status is a valid value
-	return FindVenuesByStatus(status)
-}
+// VenueLocation is the timezone venue opening hours are expressed in
+var VenueLocation = loadVenueLocation()
 
-// UpdateVenueStatus is the service-layer function to update a status
-// It now manages a transaction to also upgrade the user's role
-func UpdateVenueStatus(venueID int64, newStatus string) error {
-	// 1. Start a new database transaction
-	tx, err := db.DB.Begin()
+func loadVenueLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Kolkata")
 	if err != nil {
-		log.Println("Error starting transaction:", err)
-		return err
+		return time.FixedZone("IST", 5*60*60+30*60)
 	}
-	// 2. Defer a rollback in case anything goes wrong
-	defer tx.Rollback()
+	return loc
+}
 
-	// 3. Get the Owner's ID from the venue
-	ownerID, err := GetVenueOwner(tx, venueID)
-	if err != nil {
-		return err
+// MaxAvailabilityWindow caps the length of an availability search
+const MaxAvailabilityWindow = 12 * time.Hour
+
+// Page size limits for venue search
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+)
+
+// SearchVenues validates search params and returns one page of approved
+// venues plus an opaque cursor for the next page ("" on the last page).
+func SearchVenues(p *VenueSearchParams) ([]Venue, string, error) {
+	if p.Limit <= 0 {
+		p.Limit = DefaultSearchLimit
+	}
+	if p.Limit > MaxSearchLimit {
+		p.Limit = MaxSearchLimit
+	}
+	if p.MinPrice != nil && p.MaxPrice != nil && *p.MinPrice > *p.MaxPrice {
+		return nil, "", errors.New("min_price cannot be greater than max_price")
+	}
+	if p.Sport != "" {
+		// Accept names and aliases ("Soccer"); unknown sports simply match nothing
+		if sports, err := taxonomy.ResolveSports([]string{p.Sport}); err == nil {
+			p.Sport = sports[0].Slug
+		} else {
+			p.Sport = taxonomy.Slugify(p.Sport)
+		}
+	}
+	if p.MinRating != nil && (*p.MinRating < 1 || *p.MinRating > 5) {
+		return nil, "", errors.New("min_rating must be between 1 and 5")
+	}
+	if p.OpenAt != "" {
+		t, err := time.Parse("15:04", p.OpenAt)
+		if err != nil {
+			return nil, "", errors.New("open_at must be HH:MM")
+		}
+		p.OpenAt = t.Format("15:04:05")
 	}
 
-	// 4. Update the venue status
-	err = UpdateVenueStatusInDB(tx, venueID, newStatus)
-	if err != nil {
-		return err
+	if w := p.Available; w != nil {
+		if !w.End.After(w.Start) {
+			return nil, "", errors.New("end must be after start")
+		}
+		if w.End.Sub(w.Start) > MaxAvailabilityWindow {
+			return nil, "", errors.New("time window cannot be longer than 12 hours")
+		}
+
+		// Opening hours are stored in venue-local time
+		start, end := w.Start.In(VenueLocation), w.End.In(VenueLocation)
+		w.startTOD = start.Format("15:04:05")
+		w.endTOD = end.Format("15:04:05")
+		if end.YearDay() != start.YearDay() || end.Year() != start.Year() {
+			midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, VenueLocation)
+			if !end.Equal(midnight) {
+				return nil, "", errors.New("time window must be within a single day")
+			}
+			w.endTOD = "24:00:00"
+		}
 	}
 
-	// 5. Check if we should upgrade the user's role
-	if newStatus == "approved" {
-		log.Printf("Venue %d approved. Upgrading user %d to 'owner'", venueID, ownerID)
-		err = user.UpdateUserRole(tx, ownerID, "owner")
-		if err != nil {
-			return err
+	hasLocation := p.Lat != nil && p.Lng != nil
+	switch p.Sort {
+	case "":
+		p.Sort = SortNewest
+		if hasLocation {
+			p.Sort = SortDistance
 		}
+	case SortNewest, SortPrice, SortPriceDesc, SortRating:
+	case SortDistance:
+		if !hasLocation {
+			return nil, "", errors.New("sort=distance requires near=lat,lng")
+		}
+	default:
+		return nil, "", errors.New("sort must be one of newest, price, price_desc, rating, distance")
 	}
 
-	if newStatus == "approved" {
-		log.Printf("Venue %d approved...", venueID)
-		err = user.UpdateUserRole(tx, ownerID, "owner")
+	var cursor *venueCursor
+	if p.Cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(p.Cursor)
 		if err != nil {
-			return err
+			return nil, "", errors.New("invalid cursor")
+		}
+		cursor = &venueCursor{}
+		if err := json.Unmarshal(raw, cursor); err != nil {
+			return nil, "", errors.New("invalid cursor")
+		}
+		if cursor.Sort != p.Sort {
+			return nil, "", errors.New("cursor belongs to a different sort order")
 		}
+	}
+
+	venues, err := SearchApprovedVenues(p, cursor)
+	if err != nil {
+		return nil, "", errors.New("could not search venues")
+	}
 
-		// --- NEW: SEND NOTIFICATION ---
-		// Note: Ideally this should be inside the transaction or async,
-		// but calling it directly is fine for now.
-		_ = notification.CreateNotification(ownerID, "Your venue has been APPROVED! You are now an Owner.", "success")
-	} else if newStatus == "rejected" {
-		_ = notification.CreateNotification(ownerID, "Your venue listing was rejected by the admin.", "error")
+	next := ""
+	if len(venues) > p.Limit {
+		venues = venues[:p.Limit]
+		last := venues[p.Limit-1]
+		c := venueCursor{Sort: p.Sort, ID: last.ID}
+		switch p.Sort {
+		case SortPrice, SortPriceDesc:
+			c.Value = last.PricePerHour
+		case SortRating:
+			c.Value = last.AverageRating
+		case SortDistance:
+			c.Value = last.distanceKm
+		}
+		raw, _ := json.Marshal(c)
+		next = base64.RawURLEncoding.EncodeToString(raw)
 	}
 
-	// 6. If all queries were successful, commit the transaction
-	return tx.Commit()
+	if err := attachAmenities(venues); err != nil {
+		log.Println("Error attaching amenities:", err)
+	}
+	if err := attachCoverPhotos(venues); err != nil {
+		log.Println("Error attaching cover photos:", err)
+	}
+	return venues, next, nil
+}
+
+// attachAmenities fills in the sport and amenity lists of each venue,
+// one query for each
+func attachAmenities(venues []Venue) error {
+	ids := make([]int64, len(venues))
+	for i, v := range venues {
+		ids[i] = v.ID
+	}
+	sports, err := FindSportsForVenues(ids)
+	if err != nil {
+		return err
+	}
+	amenities, err := FindAmenitiesForVenues(ids)
+	if err != nil {
+		return err
+	}
+	for i := range venues {
+		venues[i].Sports = sports[venues[i].ID]
+		venues[i].Amenities = amenities[venues[i].ID]
+	}
+	return nil
+}
+
+// normalizeAmenities slugifies and de-duplicates amenity filters
+func normalizeAmenities(in []string) []string {
+	seen := make(map[string]bool)
+	out := make([]string, 0, len(in))
+	for _, a := range in {
+		a = taxonomy.Slugify(a)
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		out = append(out, a)
+	}
+	return out
+}
+
+// GetVenuesByStatus is the service-layer function to get venues
+func GetVenuesByStatus(status string) ([]Venue, error) {
+	// You could add validation here, e.g., check if status is a valid value
+	venues, err := FindVenuesByStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	if err := attachAmenities(venues); err != nil {
+		log.Println("Error attaching amenities:", err)
+	}
+	if err := attachCoverPhotos(venues); err != nil {
+		log.Println("Error attaching cover photos:", err)
+	}
+	return venues, nil
 }
 
 // GetVenueByID is the service-layer function to get a single venue
 func GetVenueByID(venueID int64) (*Venue, error) {
-	return FindApprovedVenueByID(venueID)
+	v, err := FindApprovedVenueByID(venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	venues := []Venue{*v}
+	if err := attachAmenities(venues); err == nil {
+		v = &venues[0]
+	}
+	covers, err := FindCoverPhotos([]int64{venueID})
+	if err == nil {
+		v.CoverPhoto = covers[venueID]
+	}
+	return v, nil
 }
 
 func GetVenuePhotos(venueID int64) ([]VenuePhoto, error) {
 	return GetPhotosByVenueID(venueID)
 }
 
+// MaxPhotoCaptionLength is the longest caption accepted for a venue photo
+const MaxPhotoCaptionLength = 200
+
+// NormalizePhotoCaption trims a caption and checks its length
+func NormalizePhotoCaption(caption string) (string, error) {
+	caption = strings.TrimSpace(caption)
+	if len(caption) > MaxPhotoCaptionLength {
+		return "", fmt.Errorf("caption must be at most %d characters", MaxPhotoCaptionLength)
+	}
+	return caption, nil
+}
+
+// AddVenuePhoto validates an upload, stores its full/card/thumbnail
+// variants and appends the photo to the end of the venue's gallery.
+// The caption must already be normalized.
+func AddVenuePhoto(venueID int64, file *multipart.FileHeader, caption string) (*VenuePhoto, error) {
+	position, err := NextPhotoPosition(venueID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	encoded, objects, err := processUpload(file, MaxVenuePhotoBytes, "playarena_venues", venuePhotoVariants...)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &VenuePhoto{VenueID: venueID, Position: position, Caption: caption}
+	for i, e := range encoded {
+		switch e.Variant {
+		case imaging.Full:
+			photo.ImageURL, photo.StorageKey = objects[i].URL, objects[i].Key
+			photo.Width, photo.Height = e.Width, e.Height
+		case imaging.Card:
+			photo.CardURL, photo.CardKey = objects[i].URL, objects[i].Key
+		case imaging.Thumbnail:
+			photo.ThumbnailURL, photo.ThumbnailKey = objects[i].URL, objects[i].Key
+		}
+	}
+
+	if err := CreateVenuePhoto(photo); err != nil {
+		deleteStoredFiles(photo.storageKeys()...)
+		return nil, errors.New("failed to store image URL")
+	}
+
+	// The first photo of a venue becomes its cover
+	if err := RefreshCoverPhoto(venueID); err != nil {
+		log.Printf("Error refreshing cover photo for venue %d: %v", venueID, err)
+	}
+	if p, err := FindVenuePhotoByID(photo.ID); err == nil {
+		photo = p
+	}
+	return photo, nil
+}
+
+// DeleteVenuePhoto removes the photo row, then its files from storage.
+// If it was the cover, the next photo in the gallery takes its place.
+// Ownership is checked by the handler.
 func DeleteVenuePhoto(photoID int64) error {
-	// TODO: Add logic to confirm the user (from token) owns this photo
-	return DeletePhoto(photoID)
+	photo, err := FindVenuePhotoByID(photoID)
+	if err != nil {
+		return errors.New("photo not found")
+	}
+	if err := DeletePhoto(photoID); err != nil {
+		return err
+	}
+
+	if photo.IsCover {
+		if err := RefreshCoverPhoto(photo.VenueID); err != nil {
+			log.Printf("Error refreshing cover photo for venue %d: %v", photo.VenueID, err)
+		}
+	}
+
+	// Photos uploaded before the storage package have no keys
+	deleteStoredFiles(photo.storageKeys()...)
+	return nil
+}
+
+// UpdateVenuePhotoCaption sets or clears a photo's caption
+func UpdateVenuePhotoCaption(photoID int64, caption string) (*VenuePhoto, error) {
+	caption, err := NormalizePhotoCaption(caption)
+	if err != nil {
+		return nil, err
+	}
+	if err := UpdatePhotoCaption(photoID, caption); err != nil {
+		return nil, errors.New("failed to update caption")
+	}
+	return FindVenuePhotoByID(photoID)
+}
+
+// ReorderVenuePhotos applies a new gallery order. The list must contain
+// every photo of the venue exactly once.
+func ReorderVenuePhotos(venueID int64, photoIDs []int64) ([]VenuePhoto, error) {
+	photos, err := GetPhotosByVenueID(venueID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+
+	current := make(map[int64]bool, len(photos))
+	for _, p := range photos {
+		current[p.ID] = true
+	}
+	if len(photoIDs) != len(photos) {
+		return nil, errors.New("photo_ids must list every photo of the venue exactly once")
+	}
+	seen := make(map[int64]bool, len(photoIDs))
+	for _, id := range photoIDs {
+		if !current[id] || seen[id] {
+			return nil, errors.New("photo_ids must list every photo of the venue exactly once")
+		}
+		seen[id] = true
+	}
+
+	if err := UpdatePhotoPositions(venueID, photoIDs); err != nil {
+		return nil, errors.New("failed to reorder photos")
+	}
+	return GetPhotosByVenueID(venueID)
+}
+
+// SetVenueCoverPhoto makes one of the venue's photos its cover
+func SetVenueCoverPhoto(venueID, photoID int64) error {
+	photo, err := FindVenuePhotoByID(photoID)
+	if err != nil || photo.VenueID != venueID {
+		return errors.New("photo not found for this venue")
+	}
+	if err := SetCoverPhoto(venueID, photoID); err != nil {
+		return errors.New("failed to set cover photo")
+	}
+	return nil
+}
+
+// attachCoverPhotos fills in the cover photo of each venue with one query
+func attachCoverPhotos(venues []Venue) error {
+	ids := make([]int64, len(venues))
+	for i, v := range venues {
+		ids[i] = v.ID
+	}
+	covers, err := FindCoverPhotos(ids)
+	if err != nil {
+		return err
+	}
+	for i := range venues {
+		venues[i].CoverPhoto = covers[venues[i].ID]
+	}
+	return nil
 }
 
 // GetVenuesForOwner is the service-layer function
 func GetVenuesForOwner(ownerID int64) ([]Venue, error) {
-	return FindVenuesByOwnerID(ownerID)
+	venues, err := FindVenuesByOwnerID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := attachAmenities(venues); err != nil {
+		log.Println("Error attaching amenities:", err)
+	}
+	if err := attachCoverPhotos(venues); err != nil {
+		log.Println("Error attaching cover photos:", err)
+	}
+	return venues, nil
 }
 
 // venue/venue_service.go
 // ... (keep existing functions)
 
-func AddReview(venueID, userID int64, rating int, comment string) error {
-	// TODO: Check if user has actually booked this venue before reviewing?
+// Review errors the handlers map to specific HTTP statuses
+var (
+	ErrReviewNotFound = errors.New("review not found or you are not its author")
+	ErrReviewExists   = errors.New("you have already reviewed this booking")
+)
+
+// ValidateRating makes sure a rating is a whole number of stars
+func ValidateRating(rating int) error {
+	if rating < 1 || rating > 5 {
+		return errors.New("rating must be between 1 and 5")
+	}
+	return nil
+}
+
+// AddReview creates a verified review: the user must have a completed,
+// confirmed booking at this venue, and each booking can be reviewed once.
+// Slots the venue blocked are not stays, and the venue's own owner and
+// staff cannot review it.
+func AddReview(venueID, userID, bookingID int64, rating int, comment string) (*Review, error) {
+	if err := ValidateRating(rating); err != nil {
+		return nil, err
+	}
+
+	b, err := FindReviewableBooking(bookingID)
+	if err != nil || b.UserID != userID || b.Kind != "booking" {
+		return nil, errors.New("booking not found")
+	}
+	if b.VenueID != venueID {
+		return nil, errors.New("this booking is not for this venue")
+	}
+	if b.Status != "confirmed" || b.EndTime.After(time.Now()) {
+		return nil, errors.New("you can only review a booking after it has been completed")
+	}
+
+	insider, err := isVenueInsider(venueID, userID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if insider {
+		return nil, errors.New("you cannot review a venue you own or work at")
+	}
+
+	exists, err := ReviewExistsForBooking(bookingID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if exists {
+		return nil, ErrReviewExists
+	}
+
+	status, note := autoModerate(userID, comment, true)
 	review := &Review{
-		VenueID: venueID,
-		UserID:  userID,
-		Rating:  rating,
-		Comment: comment,
+		VenueID:          venueID,
+		UserID:           userID,
+		BookingID:        &bookingID,
+		Rating:           rating,
+		Comment:          comment,
+		ModerationStatus: status,
+		ModerationNote:   note,
+	}
+	if err := CreateReview(review); err != nil {
+		return nil, errors.New("failed to submit review")
+	}
+
+	// Keep the venue's average, count and histogram in sync
+	if err := RefreshVenueRating(venueID); err != nil {
+		log.Printf("Error refreshing rating for venue %d: %v", venueID, err)
 	}
-	return CreateReview(review)
+	return review, nil
 }
 
-// venue/venue_service.go
+// isVenueInsider reports whether the user owns the venue or is on its staff
+func isVenueInsider(venueID, userID int64) (bool, error) {
+	isOwner, err := IsVenueOwner(venueID, userID)
+	if err != nil || isOwner {
+		return isOwner, err
+	}
+	status, _, err := FindStaffGrant(venueID, userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return status == StaffActive, nil
+}
 
-// venue/venue_service.go
+// EditReview lets the author change their rating and comment
+func EditReview(reviewID, userID int64, rating int, comment string) error {
+	if err := ValidateRating(rating); err != nil {
+		return err
+	}
 
-func ModifyVenue(venueID int64, venueData *Venue) error {
-	// TODO: Add validation (e.g. ensure price is positive)
-	venueData.ID = venueID
-	return UpdateVenueDetails(venueData)
+	review, err := FindReviewByID(reviewID)
+	if err != nil || review.UserID != userID {
+		return ErrReviewNotFound
+	}
+
+	status, note := autoModerate(userID, comment, false)
+	if err := UpdateReview(reviewID, userID, rating, comment, status, note); err != nil {
+		return err
+	}
+	if err := RefreshVenueRating(review.VenueID); err != nil {
+		log.Printf("Error refreshing rating for venue %d: %v", review.VenueID, err)
+	}
+	return nil
 }
 
-func GetVenueReviews(venueID int64) ([]Review, error) {
-	return GetReviewsByVenueID(venueID)
+// RemoveReview lets the author delete their review
+func RemoveReview(reviewID, userID int64) error {
+	review, err := FindReviewByID(reviewID)
+	if err != nil || review.UserID != userID {
+		return ErrReviewNotFound
+	}
+
+	photos, _ := FindReviewPhotos([]int64{reviewID})
+
+	if err := DeleteReview(reviewID, userID); err != nil {
+		return err
+	}
+	destroyReviewPhotos(photos[reviewID])
+
+	if err := RefreshVenueRating(review.VenueID); err != nil {
+		log.Printf("Error refreshing rating for venue %d: %v", review.VenueID, err)
+	}
+	return nil
 }
 
-// venue/venue_service.go
+// ReportReview files an abuse report against someone else's review
+func ReportReview(reviewID, reporterID int64, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required")
+	}
+	if len(reason) > 500 {
+		return errors.New("reason is too long")
+	}
 
-func VerifyVenueOwnership(venueID int64, userID int64) error {
-	isOwner, err := IsVenueOwner(venueID, userID)
+	review, err := FindReviewByID(reviewID)
+	if err != nil || review.ModerationStatus != ReviewPublished {
+		return errors.New("review not found")
+	}
+	if review.UserID == reporterID {
+		return errors.New("you cannot report your own review")
+	}
+
+	if err := CreateReviewReport(reviewID, reporterID, reason); err != nil {
+		return errors.New("you have already reported this review")
+	}
+	return nil
+}
+
+// GetModerationQueue is the service-layer function for the admin queue
+func GetModerationQueue() ([]ModerationItem, error) {
+	return FindModerationQueue()
+}
+
+// ModerateReview applies an admin decision ("hide" or "restore") to a
+// review, closes its open reports and refreshes the venue's aggregates.
+func ModerateReview(reviewID, adminID int64, action, note string) error {
+	review, err := FindReviewByID(reviewID)
 	if err != nil {
-		return err
+		return ErrReviewNotFound
 	}
-	if !isOwner {
-		return errors.New("you do not have permission to modify this venue")
+
+	var status, message string
+	switch action {
+	case "hide":
+		status, message = ReviewHidden, "Your review was hidden by a moderator."
+	case "restore":
+		status, message = ReviewPublished, "Your review has been published."
+	default:
+		return errors.New("action must be 'hide' or 'restore'")
+	}
+
+	if err := SetReviewModerationStatus(reviewID, status, note); err != nil {
+		return errors.New("failed to update review")
+	}
+	if err := ResolveReviewReports(reviewID, adminID); err != nil {
+		log.Printf("Error resolving reports for review %d: %v", reviewID, err)
+	}
+	if err := RefreshVenueRating(review.VenueID); err != nil {
+		log.Printf("Error refreshing rating for venue %d: %v", review.VenueID, err)
+	}
+
+	if status != review.ModerationStatus {
+		_ = notification.CreateNotification(review.UserID, message, "info")
+	}
+	return nil
+}
+
+// AdminDeleteReview permanently removes a review (admin moderation)
+func AdminDeleteReview(reviewID int64) error {
+	review, err := FindReviewByID(reviewID)
+	if err != nil {
+		return ErrReviewNotFound
 	}
+
+	photos, _ := FindReviewPhotos([]int64{reviewID})
+
+	if err := DeleteReviewByID(reviewID); err != nil {
+		return errors.New("failed to delete review")
+	}
+	destroyReviewPhotos(photos[reviewID])
+	if err := RefreshVenueRating(review.VenueID); err != nil {
+		log.Printf("Error refreshing rating for venue %d: %v", review.VenueID, err)
+	}
+
+	_ = notification.CreateNotification(review.UserID, "Your review was removed by a moderator.", "info")
 	return nil
 }
+
+// Review photo limits
+const (
+	defaultMaxReviewPhotos = 5
+	MaxReviewPhotoBytes    = 5 << 20 // 5 MB
+)
+
+// maxReviewPhotos reads REVIEW_MAX_PHOTOS (photos allowed per review)
+func maxReviewPhotos() int {
+	n, err := strconv.Atoi(os.Getenv("REVIEW_MAX_PHOTOS"))
+	if err != nil || n <= 0 {
+		return defaultMaxReviewPhotos
+	}
+	return n
+}
+
+// AddReviewPhoto uploads a photo to the author's review
+func AddReviewPhoto(reviewID, userID int64, file *multipart.FileHeader) (*ReviewPhoto, error) {
+	review, err := FindReviewByID(reviewID)
+	if err != nil || review.UserID != userID {
+		return nil, ErrReviewNotFound
+	}
+
+	// Checked up front so a full review doesn't cost an upload, and again
+	// under lock below, where it counts
+	count, err := CountReviewPhotos(nil, reviewID)
+	if err != nil {
+		return nil, errors.New("database error")
+	}
+	if count >= maxReviewPhotos() {
+		return nil, fmt.Errorf("a review can have at most %d photos", maxReviewPhotos())
+	}
+
+	// Re-encoded like venue photos, which also drops EXIF/GPS metadata
+	_, objects, err := processUpload(file, MaxReviewPhotoBytes, "playarena_reviews", imaging.Full)
+	if err != nil {
+		return nil, err
+	}
+	obj := objects[0]
+
+	photo := &ReviewPhoto{ReviewID: reviewID, ImageURL: obj.URL, StorageKey: obj.Key}
+	if err := saveReviewPhoto(photo); err != nil {
+		destroyReviewPhotos([]ReviewPhoto{*photo})
+		return nil, err
+	}
+	return photo, nil
+}
+
+// saveReviewPhoto records an uploaded photo, counting the review's photos
+// under a lock on the review so concurrent uploads can't pass the limit
+func saveReviewPhoto(photo *ReviewPhoto) error {
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return errors.New("failed to save photo")
+	}
+	defer tx.Rollback()
+
+	if err := LockReview(tx, photo.ReviewID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrReviewNotFound
+		}
+		return errors.New("failed to save photo")
+	}
+	count, err := CountReviewPhotos(tx, photo.ReviewID)
+	if err != nil {
+		return errors.New("failed to save photo")
+	}
+	if count >= maxReviewPhotos() {
+		return fmt.Errorf("a review can have at most %d photos", maxReviewPhotos())
+	}
+	if err := CreateReviewPhoto(tx, photo); err != nil {
+		return errors.New("failed to save photo")
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.New("failed to save photo")
+	}
+	return nil
+}
+
+// RemoveReviewPhoto deletes a photo from a review (author or admin)
+// and removes the asset from storage.
+func RemoveReviewPhoto(reviewID, photoID, userID int64, userRole string) error {
+	photo, err := FindReviewPhotoByID(photoID)
+	if err != nil || photo.ReviewID != reviewID {
+		return errors.New("photo not found")
+	}
+
+	if userRole != "admin" {
+		review, err := FindReviewByID(reviewID)
+		if err != nil || review.UserID != userID {
+			return ErrReviewNotFound
+		}
+	}
+
+	if err := DeleteReviewPhotoRow(photoID); err != nil {
+		return errors.New("failed to delete photo")
+	}
+	destroyReviewPhotos([]ReviewPhoto{*photo})
+	return nil
+}
+
+// destroyReviewPhotos removes review photo assets from storage.
+// Failures are logged; the DB rows are already gone at this point.
+func destroyReviewPhotos(photos []ReviewPhoto) {
+	keys := make([]string, len(photos))
+	for i, p := range photos {
+		keys[i] = p.StorageKey
+	}
+	deleteStoredFiles(keys...)
+}
+
+// Reply errors the handlers map to specific HTTP statuses
+var (
+	ErrReplyReviewNotFound = errors.New("review not found")
+	ErrEmptyReply          = errors.New("reply cannot be empty")
+	ErrReviewNotPublished  = errors.New("only published reviews can be replied to")
+)
+
+// ReplyToReview sets the venue owner's public reply (one per review;
+// replying again replaces the previous text). Access is checked by the
+// route policy: the venue's owner, an admin, or staff who edit the listing.
+// Reviews held or hidden by moderation can't be replied to.
+func ReplyToReview(reviewID, userID int64, userRole string, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ErrEmptyReply
+	}
+
+	review, err := FindReviewByID(reviewID)
+	if err == sql.ErrNoRows {
+		return ErrReplyReviewNotFound
+	}
+	if err != nil {
+		return errors.New("database error")
+	}
+
+	if review.ModerationStatus != ReviewPublished {
+		return ErrReviewNotPublished
+	}
+
+	// Conditional on the status too, in case moderation holds it meanwhile
+	saved, err := SaveReviewReply(reviewID, userID, text)
+	if err != nil {
+		return errors.New("failed to save reply")
+	}
+	if !saved {
+		return ErrReviewNotPublished
+	}
+	RecordStaffAction(review.VenueID, userID, userRole, "review_replied", fmt.Sprintf("review %d", reviewID))
+
+	_ = notification.CreateNotification(review.UserID, "The venue owner replied to your review.", "info")
+	return nil
+}
+
+// RemoveReviewReply deletes the owner's reply from a review
+func RemoveReviewReply(reviewID, userID int64, userRole string) error {
+	review, err := FindReviewByID(reviewID)
+	if err == sql.ErrNoRows {
+		return ErrReplyReviewNotFound
+	}
+	if err != nil {
+		return errors.New("database error")
+	}
+
+	if err := DeleteReviewReply(reviewID); err != nil {
+		return errors.New("failed to remove reply")
+	}
+	RecordStaffAction(review.VenueID, userID, userRole, "review_reply_deleted", fmt.Sprintf("review %d", reviewID))
+	return nil
+}
+
+// ModifyVenue saves a full edit (PUT). On an approved venue, an owner's changes
+// to sensitive fields are staged as a pending revision (returned) while the
+// rest of the edit applies immediately. Admin edits always apply directly.
+// expectedVersion is the version the client last saw (or AnyVersion).
+func ModifyVenue(venueID int64, venueData *Venue, expectedVersion int, userID int64, userRole string) (*VenueRevision, error) {
+	if err := validateVenue(venueData); err != nil {
+		return nil, err
+	}
+
+	live, err := loadVenue(venueID)
+	if err != nil {
+		return nil, ErrVenueNotFound
+	}
+	if err := checkVersion(expectedVersion, live.Version); err != nil {
+		return nil, err
+	}
+	return saveVenueEdit(live, venueData, userID, userRole)
+}
+
+// saveVenueEdit writes an already validated edit over the live venue. The
+// write is guarded by the live version, so a concurrent edit made since the
+// venue was loaded fails with ErrVersionConflict instead of being overwritten.
+// On success venueData.Version holds the new version.
+func saveVenueEdit(live, venueData *Venue, userID int64, userRole string) (*VenueRevision, error) {
+	if live.Lifecycle == LifecycleArchived {
+		return nil, ErrVenueArchived
+	}
+	sportIDs, err := resolveVenueSports(venueData)
+	if err != nil {
+		return nil, err
+	}
+	amenityIDs, err := resolveVenueAmenities(venueData)
+	if err != nil {
+		return nil, err
+	}
+
+	var staged *VenueRevisionFields
+	if live.Status == StatusApproved && userRole != "admin" {
+		staged = stageSensitiveChanges(live, venueData)
+	}
+
+	// The details, both link lists and the staged revision change
+	// together; a half-applied edit would carry the new version and turn
+	// the client's retry into a conflict, or lose the staged fields
+	tx, err := db.DB.Begin()
+	if err != nil {
+		log.Println("Error starting transaction:", err)
+		return nil, errors.New("failed to update venue")
+	}
+	defer tx.Rollback()
+
+	venueID := live.ID
+	venueData.ID = venueID
+	venueData.Version = live.Version
+	updated, err := UpdateVenueDetails(tx, venueData)
+	if err != nil {
+		return nil, errors.New("failed to update venue")
+	}
+	if !updated {
+		return nil, ErrVersionConflict
+	}
+
+	if staged == nil || staged.Sports == nil {
+		if err := ReplaceVenueSports(tx, venueID, sportIDs); err != nil {
+			return nil, errors.New("failed to update sports")
+		}
+	}
+	// Amenities are only replaced when the client sends the list
+	if amenityIDs != nil {
+		if err := ReplaceVenueAmenities(tx, venueID, amenityIDs); err != nil {
+			return nil, errors.New("failed to update amenities")
+		}
+	}
+
+	var revisionID int64
+	switch {
+	case staged == nil:
+	case staged.isEmpty():
+		// The edit matches the live values again; nothing left to review
+		if err := DeletePendingRevision(tx, venueID); err != nil {
+			return nil, errors.New("failed to update pending changes")
+		}
+	default:
+		if revisionID, err = SavePendingRevision(tx, venueID, userID, staged); err != nil {
+			return nil, errors.New("failed to save changes for review")
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errors.New("failed to update venue")
+	}
+	if revisionID == 0 {
+		return nil, nil
+	}
+
+	revision, err := FindRevisionByID(revisionID)
+	if err != nil {
+		return nil, errors.New("failed to load pending changes")
+	}
+	return withDiff(revision)
+}
+
+func GetVenueReviews(venueID int64) ([]Review, error) {
+	reviews, err := GetReviewsByVenueID(venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(reviews))
+	for i, r := range reviews {
+		ids[i] = r.ID
+	}
+	photos, err := FindReviewPhotos(ids)
+	if err != nil {
+		log.Println("Error attaching review photos:", err)
+	}
+	for i := range reviews {
+		reviews[i].Photos = photos[reviews[i].ID]
+		if reviews[i].Photos == nil {
+			reviews[i].Photos = make([]ReviewPhoto, 0)
+		}
+	}
+	return reviews, nil
+}
+
 // venue/venue_service.go
 
 // GetVenueIdFromPhoto service wrapper
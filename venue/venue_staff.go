@@ -0,0 +1,229 @@
+// venue/venue_staff.go
+package venue
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JkD004/playarena-backend/notification"
+	"github.com/JkD004/playarena-backend/policy"
+	"github.com/JkD004/playarena-backend/user"
+)
+
+// Staff permissions an owner can grant on a venue. The names are defined in
+// policy, whose route rules check them.
+const (
+	PermViewBookings = policy.StaffViewBookings
+	PermBlockSlots   = policy.StaffBlockSlots
+	PermCheckIn      = policy.StaffCheckIn
+	PermEditListing  = policy.StaffEditListing // details, photos and review replies
+	PermViewRevenue  = policy.StaffViewRevenue
+)
+
+// StaffPermissions lists every grantable permission, in display order
+var StaffPermissions = []string{PermViewBookings, PermBlockSlots, PermCheckIn, PermEditListing, PermViewRevenue}
+
+// Staff grant states
+const (
+	StaffPending = "pending"
+	StaffActive  = "active"
+)
+
+// maxStaffActions caps GET /venues/:id/staff/actions
+const maxStaffActions = 200
+
+// ErrNoVenuePermission is returned when a user may not act on a venue
+var ErrNoVenuePermission = errors.New("you do not have permission to manage this venue")
+
+// VerifyVenuePermission allows admins, the venue's owner, and active staff
+// who have been granted perm.
+func VerifyVenuePermission(venueID, userID int64, userRole, perm string) error {
+	if userRole == "admin" {
+		return nil
+	}
+	isOwner, err := IsVenueOwner(venueID, userID)
+	if err != nil {
+		return err
+	}
+	if isOwner {
+		return nil
+	}
+
+	status, perms, err := FindStaffGrant(venueID, userID)
+	if err == sql.ErrNoRows {
+		return ErrNoVenuePermission
+	}
+	if err != nil {
+		return err
+	}
+	if status != StaffActive {
+		return ErrNoVenuePermission
+	}
+	for _, p := range perms {
+		if p == perm {
+			return nil
+		}
+	}
+	return ErrNoVenuePermission
+}
+
+// RecordStaffAction adds an entry to the venue's audit trail when the actor
+// is a staff member. Owner and admin actions are not recorded.
+func RecordStaffAction(venueID, userID int64, userRole, action, details string) {
+	if userRole == "admin" {
+		return
+	}
+	if isOwner, err := IsVenueOwner(venueID, userID); err != nil || isOwner {
+		return
+	}
+	_ = CreateStaffAction(venueID, userID, action, details)
+}
+
+// normalizePermissions checks a requested permission list and returns it
+// de-duplicated, in StaffPermissions order
+func normalizePermissions(perms []string) ([]string, error) {
+	requested := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		p = strings.ToLower(strings.TrimSpace(p))
+		known := false
+		for _, sp := range StaffPermissions {
+			if p == sp {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, errors.New("unknown permission: " + p)
+		}
+		requested[p] = true
+	}
+	if len(requested) == 0 {
+		return nil, errors.New("at least one permission is required")
+	}
+
+	normalized := make([]string, 0, len(requested))
+	for _, sp := range StaffPermissions {
+		if requested[sp] {
+			normalized = append(normalized, sp)
+		}
+	}
+	return normalized, nil
+}
+
+// InviteStaff invites a registered user to help run a venue
+func InviteStaff(venueID, actorID int64, email string, perms []string) error {
+	perms, err := normalizePermissions(perms)
+	if err != nil {
+		return err
+	}
+	v, err := FindVenueByID(venueID)
+	if err != nil {
+		return ErrVenueNotFound
+	}
+	if v.Lifecycle == LifecycleArchived {
+		return ErrVenueArchived
+	}
+
+	invitee, err := user.GetUserByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return errors.New("user with that email not found")
+	}
+	if invitee.ID == v.OwnerID {
+		return errors.New("the owner cannot be added as staff")
+	}
+	if status, _, err := FindStaffGrant(venueID, invitee.ID); err == nil {
+		if status == StaffPending {
+			return errors.New("user has already been invited")
+		}
+		return errors.New("user is already staff at this venue")
+	} else if err != sql.ErrNoRows {
+		return errors.New("database error")
+	}
+
+	if err := CreateStaffInvite(venueID, invitee.ID, actorID, perms); err != nil {
+		return errors.New("failed to invite user")
+	}
+	_ = CreateStaffAction(venueID, actorID, "staff_invited",
+		fmt.Sprintf("%s (%s)", invitee.Email, strings.Join(perms, ", ")))
+	_ = notification.CreateNotification(invitee.ID, "You have been invited to join the staff of "+v.Name+".", "info")
+	return nil
+}
+
+// UpdateStaffPermissions replaces a staff member's permissions
+func UpdateStaffPermissions(venueID, actorID, staffUserID int64, perms []string) error {
+	perms, err := normalizePermissions(perms)
+	if err != nil {
+		return err
+	}
+	updated, err := UpdateStaffPermissionsInDB(venueID, staffUserID, perms)
+	if err != nil {
+		return errors.New("failed to update permissions")
+	}
+	if !updated {
+		return errors.New("staff member not found")
+	}
+	_ = CreateStaffAction(venueID, actorID, "staff_permissions_changed",
+		fmt.Sprintf("user %d: %s", staffUserID, strings.Join(perms, ", ")))
+	return nil
+}
+
+// RemoveStaff revokes a staff member's access (or withdraws an invite)
+func RemoveStaff(venueID, actorID, staffUserID int64) error {
+	removed, err := DeleteStaff(venueID, staffUserID)
+	if err != nil {
+		return errors.New("failed to remove staff member")
+	}
+	if !removed {
+		return errors.New("staff member not found")
+	}
+	_ = CreateStaffAction(venueID, actorID, "staff_removed", fmt.Sprintf("user %d", staffUserID))
+	_ = notification.CreateNotification(staffUserID, "Your staff access to a venue has been removed.", "info")
+	return nil
+}
+
+// RespondToStaffInvite lets a user accept or decline an invite. Declining an
+// accepted grant means leaving the venue's staff.
+func RespondToStaffInvite(venueID, userID int64, status string) error {
+	switch status {
+	case "accepted":
+		activated, err := ActivateStaff(venueID, userID)
+		if err != nil {
+			return errors.New("failed to accept invite")
+		}
+		if !activated {
+			return errors.New("no pending invite for this venue")
+		}
+		_ = CreateStaffAction(venueID, userID, "staff_joined", "")
+
+	case "declined":
+		removed, err := DeleteStaff(venueID, userID)
+		if err != nil {
+			return errors.New("failed to decline invite")
+		}
+		if !removed {
+			return errors.New("no invite for this venue")
+		}
+		_ = CreateStaffAction(venueID, userID, "staff_left", "")
+
+	default:
+		return errors.New("invalid status: must be 'accepted' or 'declined'")
+	}
+	return nil
+}
+
+// GetVenueStaff lists a venue's staff and open invites
+func GetVenueStaff(venueID int64) ([]StaffMember, error) {
+	return FindStaffForVenue(venueID)
+}
+
+// GetStaffAssignments lists the venues a user works at or is invited to
+func GetStaffAssignments(userID int64) ([]StaffAssignment, error) {
+	return FindStaffAssignments(userID)
+}
+
+// GetStaffActions returns the latest entries of a venue's staff audit trail
+func GetStaffActions(venueID int64) ([]StaffAction, error) {
+	return FindStaffActions(venueID, maxStaffActions)
+}